@@ -36,3 +36,22 @@ func GenBFSList() []int {
 	}
 	return res
 }
+
+// GenUniqList 生成一个随机且不包含重复元素的序列
+//
+// GenBFSList 中的 0 可能重复，这里去重后返回
+func GenUniqList() []int {
+	var (
+		data = GenBFSList()
+		res  = make([]int, 0, len(data))
+		uniq = make(map[int]struct{}, len(data))
+	)
+	for _, v := range data {
+		if _, ok := uniq[v]; ok {
+			continue
+		}
+		uniq[v] = struct{}{}
+		res = append(res, v)
+	}
+	return res
+}
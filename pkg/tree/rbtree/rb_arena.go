@@ -0,0 +1,65 @@
+package rbtree
+
+// defaultChunkSize arena 每次批量分配的节点数量
+const defaultChunkSize = 256
+
+// nodeArena 红黑树节点的 slab 分配器
+//
+// 按块批量分配节点，减少小对象的分配次数；删除的节点放入空闲链表（复用 right 指针）
+// 优先复用，降低频繁增删时的 GC 压力。不是并发安全的
+type nodeArena[T any] struct {
+	chunk     []rbNode[T] // 当前块中尚未分配的节点
+	chunkSize int
+	freeList  *rbNode[T] // 空闲链表
+	allocated int        // 从块中分配出去的节点总数
+}
+
+// newNodeArena 创建节点分配器
+func newNodeArena[T any](chunkSize int) *nodeArena[T] {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	return &nodeArena[T]{chunkSize: chunkSize}
+}
+
+// alloc 分配一个零值节点
+func (a *nodeArena[T]) alloc() *rbNode[T] {
+	if node := a.freeList; node != nil {
+		a.freeList = node.right
+		node.right = nil
+		return node
+	}
+	if len(a.chunk) == 0 {
+		a.chunk = make([]rbNode[T], a.chunkSize)
+	}
+	node := &a.chunk[0]
+	a.chunk = a.chunk[1:]
+	a.allocated++
+	return node
+}
+
+// free 回收节点，清空节点内容以免持有无用的引用
+//
+// gen 需要保留，复用节点时旧的句柄才能发现节点已经失效
+func (a *nodeArena[T]) free(node *rbNode[T]) {
+	*node = rbNode[T]{gen: node.gen}
+	node.right = a.freeList
+	a.freeList = node
+}
+
+// derive 为派生出的红黑树创建独立的分配器，块大小保持一致；a 为 nil 时返回 nil
+func (a *nodeArena[T]) derive() *nodeArena[T] {
+	if a == nil {
+		return nil
+	}
+	return newNodeArena[T](a.chunkSize)
+}
+
+// UseArena 开启 slab 分配：节点按块批量分配，删除的节点会被回收复用
+//
+// chunkSize 为每块的节点数量，小于等于 0 时使用默认值。
+// 需要在插入元素之前调用；Split、Join 以及集合运算得到的红黑树各自使用独立的 arena，
+// 从其他树移动过来的节点删除后回收到当前树的 arena 中，不同的树可以在不同的协程中使用
+func (rb *RBTree[T]) UseArena(chunkSize int) {
+	rb.arena = newNodeArena[T](chunkSize)
+}
@@ -0,0 +1,71 @@
+package rbtree
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// benchChurn 先插入 size 个元素，再随机删除、插入，模拟频繁增删的场景
+func benchChurn(b *testing.B, useArena bool) {
+	const size = 10000
+	rd := rand.New(rand.NewSource(1))
+	keys := make([]int, size)
+	for i := range keys {
+		keys[i] = rd.Int()
+	}
+
+	rb := NewRBTree[int]()
+	if useArena {
+		rb.UseArena(0)
+	}
+	for _, k := range keys {
+		rb.Insert(k)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := range b.N {
+		idx := i % size
+		rb.Delete(keys[idx])
+		keys[idx] = rd.Int()
+		rb.Insert(keys[idx])
+	}
+}
+
+func BenchmarkChurnPointer(b *testing.B) {
+	benchChurn(b, false)
+}
+
+func BenchmarkChurnArena(b *testing.B) {
+	benchChurn(b, true)
+}
+
+// benchInsert 从空树开始插入 size 个元素
+func benchInsert(b *testing.B, useArena bool) {
+	const size = 10000
+	rd := rand.New(rand.NewSource(1))
+	keys := make([]int, size)
+	for i := range keys {
+		keys[i] = rd.Int()
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		rb := NewRBTree[int]()
+		if useArena {
+			rb.UseArena(0)
+		}
+		for _, k := range keys {
+			rb.Insert(k)
+		}
+	}
+}
+
+func BenchmarkInsertPointer(b *testing.B) {
+	benchInsert(b, false)
+}
+
+func BenchmarkInsertArena(b *testing.B) {
+	benchInsert(b, true)
+}
@@ -0,0 +1,100 @@
+package rbtree
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArena(t *testing.T) {
+	rb := NewRBTree[int]()
+	rb.UseArena(16)
+
+	data := GenUniqList()
+	for _, v := range data {
+		rb.Insert(v)
+	}
+	require.True(t, rb.IsValid())
+	require.Equal(t, len(data), rb.arena.allocated)
+
+	// 删除的节点全部被回收
+	for _, v := range data {
+		rb.Delete(v)
+	}
+	require.Equal(t, 0, rb.Len())
+
+	// 再次插入时优先复用空闲节点，不会从块中分配新的节点
+	for _, v := range data {
+		rb.Insert(v)
+	}
+	require.True(t, rb.IsValid())
+	require.Equal(t, len(data), rb.arena.allocated)
+
+	slices.Sort(data)
+	require.Equal(t, data, slices.Collect(rb.All()))
+}
+
+func TestArenaFree(t *testing.T) {
+	a := newNodeArena[int](0)
+	require.Equal(t, defaultChunkSize, a.chunkSize)
+
+	n1 := a.alloc()
+	n1.val, n1.left = 1, newRBNode(2, red)
+	a.free(n1)
+	require.Same(t, n1, a.freeList)
+	require.Zero(t, n1.val)
+	require.Nil(t, n1.left)
+
+	// 优先从空闲链表分配
+	require.Same(t, n1, a.alloc())
+	require.Nil(t, a.freeList)
+	require.Nil(t, n1.right)
+	require.Equal(t, 1, a.allocated)
+}
+
+func TestArenaSplitJoin(t *testing.T) {
+	rb := NewRBTree[int]()
+	rb.UseArena(16)
+	data := GenUniqList()
+	for _, v := range data {
+		rb.Insert(v)
+	}
+	slices.Sort(data)
+
+	// 拆分得到的两棵树各自使用独立的 arena
+	key := data[len(data)/2]
+	left, right := rb.Split(key)
+	require.NotNil(t, left.arena)
+	require.NotNil(t, right.arena)
+	require.NotSame(t, rb.arena, left.arena)
+	require.NotSame(t, left.arena, right.arena)
+	require.Equal(t, rb.arena.chunkSize, left.arena.chunkSize)
+
+	// 两棵树交替增删，互不影响
+	for i, v := range data {
+		if v < key {
+			require.True(t, left.Delete(v))
+			require.True(t, right.Insert(v+10000))
+		} else if i%2 == 0 {
+			require.True(t, right.Delete(v))
+			require.True(t, left.Insert(v-10000))
+		}
+		require.True(t, left.IsValid())
+		require.True(t, right.IsValid())
+	}
+
+	// 连接时直接复用 b 中最小的节点作为连接点
+	minNode, total := right.root.minSubNode(), left.Len()+right.Len()
+	res, err := Join(left, right)
+	require.NoError(t, err)
+	require.True(t, res.IsValid())
+	require.Same(t, minNode, res.find(minNode.val))
+	require.NotSame(t, left.arena, res.arena)
+	require.Equal(t, total, res.Len())
+
+	// 未开启 arena 时派生的树也不开启
+	a, b := NewRBTree[int]().Split(0)
+	require.Nil(t, a.arena)
+	require.Nil(t, b.arena)
+}
@@ -0,0 +1,73 @@
+package rbtree
+
+// Augmenter 用户自定义的节点增强逻辑，如区间最大值、子树和等聚合值
+//
+// 增强值作为元素 T 中的字段保存（比较函数需要忽略这些字段），节点不需要额外的空间，也不需要装箱。
+// Recompute 根据 val 自身以及左右孩子的增强值（孩子不存在时为 nil），重新计算 val 中的增强字段，
+// 只能修改增强字段，不能修改参与比较的字段，也不能保留指针。
+// 每次旋转、transplant 以及插入删除路径上的节点发生变化时都会自底向上回调，
+// 回调时左右孩子的增强值已经是最新的
+type Augmenter[T any] interface {
+	Recompute(val *T, left, right *T)
+}
+
+// AugmentFunc 函数形式的 Augmenter
+type AugmentFunc[T any] func(val *T, left, right *T)
+
+// Recompute 实现 Augmenter
+func (f AugmentFunc[T]) Recompute(val *T, left, right *T) {
+	f(val, left, right)
+}
+
+// NewRBTreeAugmented 创建维护自定义增强值的红黑树
+func NewRBTreeAugmented[T any](cmp func(a, b T) int, augmenter Augmenter[T]) *RBTree[T] {
+	rb := NewRBTreeFunc(cmp)
+	rb.augmenter = augmenter
+	return rb
+}
+
+// Node 红黑树节点的只读视图
+//
+// 零值表示空节点，可以通过 IsNil 判断
+type Node[T any] struct {
+	n *rbNode[T]
+}
+
+// Root 根节点，可以配合增强值自顶向下查询
+func (rb *RBTree[T]) Root() Node[T] {
+	return Node[T]{n: rb.root}
+}
+
+// IsNil 是否为空节点
+func (n Node[T]) IsNil() bool {
+	return n.n == nil
+}
+
+// Value 节点的值（包括其中的增强字段），空节点返回零值
+func (n Node[T]) Value() T {
+	if n.n == nil {
+		return *new(T)
+	}
+	return n.n.val
+}
+
+// Size 以当前节点为根的子树的节点数量
+func (n Node[T]) Size() int {
+	return sizeOf(n.n)
+}
+
+// Left 左孩子
+func (n Node[T]) Left() Node[T] {
+	if n.n == nil {
+		return Node[T]{}
+	}
+	return Node[T]{n: n.n.left}
+}
+
+// Right 右孩子
+func (n Node[T]) Right() Node[T] {
+	if n.n == nil {
+		return Node[T]{}
+	}
+	return Node[T]{n: n.n.right}
+}
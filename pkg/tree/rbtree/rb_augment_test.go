@@ -0,0 +1,134 @@
+package rbtree
+
+import (
+	"cmp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// sumItem 带有子树和的元素，比较时只比较 val，sum 为增强字段
+type sumItem struct {
+	val, sum int
+}
+
+// compareSumItem 只比较 val，忽略增强字段
+func compareSumItem(a, b sumItem) int {
+	return cmp.Compare(a.val, b.val)
+}
+
+// sumAugmenter 维护子树和
+var sumAugmenter = AugmentFunc[sumItem](func(val *sumItem, left, right *sumItem) {
+	val.sum = val.val
+	if left != nil {
+		val.sum += left.sum
+	}
+	if right != nil {
+		val.sum += right.sum
+	}
+})
+
+// newSumTree 创建维护子树和的红黑树
+func newSumTree() *RBTree[sumItem] {
+	return NewRBTreeAugmented(compareSumItem, sumAugmenter)
+}
+
+// sumValues 按照从小到大的顺序返回所有元素的 val
+func sumValues(rb *RBTree[sumItem]) []int {
+	res := []int{}
+	for item := range rb.All() {
+		res = append(res, item.val)
+	}
+	return res
+}
+
+// checkSubtreeSum 递归校验每个节点的增强值，返回子树和
+func checkSubtreeSum(t *testing.T, node Node[sumItem]) int {
+	if node.IsNil() {
+		return 0
+	}
+	sum := node.Value().val + checkSubtreeSum(t, node.Left()) + checkSubtreeSum(t, node.Right())
+	require.Equal(t, sum, node.Value().sum)
+	return sum
+}
+
+// prefixSum 借助子树和在 O(log n) 内求小于 val 的元素之和
+func prefixSum(rb *RBTree[sumItem], val int) int {
+	var (
+		cur = rb.Root()
+		sum = 0
+	)
+	for !cur.IsNil() {
+		if val <= cur.Value().val {
+			cur = cur.Left()
+			continue
+		}
+		sum += cur.Value().val
+		if l := cur.Left(); !l.IsNil() {
+			sum += l.Value().sum
+		}
+		cur = cur.Right()
+	}
+	return sum
+}
+
+func TestAugmenter(t *testing.T) {
+	rb := newSumTree()
+	require.True(t, rb.Root().IsNil())
+	require.Zero(t, rb.Root().Value())
+
+	data := GenUniqList()
+	total := 0
+	for idx := range data {
+		// 插入时增强字段的初始值会被覆盖
+		rb.Insert(sumItem{val: data[idx], sum: -1})
+		total += data[idx]
+	}
+	require.True(t, rb.IsValid())
+	require.Equal(t, total, checkSubtreeSum(t, rb.Root()))
+	require.Equal(t, len(data), rb.Root().Size())
+
+	for _, v := range data[:len(data)/2] {
+		rb.Delete(sumItem{val: v})
+		total -= v
+	}
+	require.True(t, rb.IsValid())
+	require.Equal(t, total, checkSubtreeSum(t, rb.Root()))
+
+	for v := -1; v <= 1001; v += 7 {
+		want := 0
+		for _, w := range sumValues(rb) {
+			if w < v {
+				want += w
+			}
+		}
+		require.Equal(t, want, prefixSum(rb, v))
+	}
+}
+
+func TestAugmenterReplace(t *testing.T) {
+	rb := newSumTree()
+	for v := range 100 {
+		rb.Insert(sumItem{val: v})
+	}
+	// 替换元素后重新计算路径上的增强值
+	old, ok := rb.Replace(sumItem{val: 50, sum: 123})
+	require.True(t, ok)
+	require.Equal(t, 50, old.val)
+	require.Equal(t, 99*100/2, checkSubtreeSum(t, rb.Root()))
+}
+
+func TestAugmenterNoAlloc(t *testing.T) {
+	rb := newSumTree()
+	rb.UseArena(0)
+	for v := range 1000 {
+		rb.Insert(sumItem{val: v})
+	}
+	// 增强值保存在元素中，维护增强值不需要分配内存
+	allocs := testing.AllocsPerRun(100, func() {
+		rb.Delete(sumItem{val: 500})
+		rb.Insert(sumItem{val: 500})
+	})
+	require.Zero(t, allocs)
+	checkSubtreeSum(t, rb.Root())
+}
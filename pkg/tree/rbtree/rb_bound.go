@@ -0,0 +1,139 @@
+package rbtree
+
+import "iter"
+
+// lowerBound 查找第一个大于等于 val 的节点，不存在返回 nil
+func (rb *RBTree[T]) lowerBound(val T) *rbNode[T] {
+	var (
+		cur = rb.root
+		res *rbNode[T]
+	)
+	for cur != nil {
+		if rb.cmp(cur.val, val) >= 0 {
+			res = cur
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+	return res
+}
+
+// upperBound 查找第一个大于 val 的节点，不存在返回 nil
+func (rb *RBTree[T]) upperBound(val T) *rbNode[T] {
+	var (
+		cur = rb.root
+		res *rbNode[T]
+	)
+	for cur != nil {
+		if rb.cmp(cur.val, val) > 0 {
+			res = cur
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+	return res
+}
+
+// floorNode 查找最后一个小于等于 val 的节点，不存在返回 nil
+func (rb *RBTree[T]) floorNode(val T) *rbNode[T] {
+	var (
+		cur = rb.root
+		res *rbNode[T]
+	)
+	for cur != nil {
+		if rb.cmp(cur.val, val) <= 0 {
+			res = cur
+			cur = cur.right
+		} else {
+			cur = cur.left
+		}
+	}
+	return res
+}
+
+// lowerNode 查找最后一个小于 val 的节点，不存在返回 nil
+func (rb *RBTree[T]) lowerNode(val T) *rbNode[T] {
+	var (
+		cur = rb.root
+		res *rbNode[T]
+	)
+	for cur != nil {
+		if rb.cmp(cur.val, val) < 0 {
+			res = cur
+			cur = cur.right
+		} else {
+			cur = cur.left
+		}
+	}
+	return res
+}
+
+// nodeValue 返回节点的值，以及节点是否存在
+func nodeValue[T any](node *rbNode[T]) (T, bool) {
+	if node == nil {
+		return *new(T), false
+	}
+	return node.val, true
+}
+
+// Floor 小于等于 val 的最大元素
+func (rb *RBTree[T]) Floor(val T) (T, bool) {
+	return nodeValue(rb.floorNode(val))
+}
+
+// Ceiling 大于等于 val 的最小元素
+func (rb *RBTree[T]) Ceiling(val T) (T, bool) {
+	return nodeValue(rb.lowerBound(val))
+}
+
+// Lower 严格小于 val 的最大元素
+func (rb *RBTree[T]) Lower(val T) (T, bool) {
+	return nodeValue(rb.lowerNode(val))
+}
+
+// Higher 严格大于 val 的最小元素
+func (rb *RBTree[T]) Higher(val T) (T, bool) {
+	return nodeValue(rb.upperBound(val))
+}
+
+// Min 最小元素
+func (rb *RBTree[T]) Min() (T, bool) {
+	if rb.root == nil {
+		return *new(T), false
+	}
+	return rb.root.minSubNode().val, true
+}
+
+// Max 最大元素
+func (rb *RBTree[T]) Max() (T, bool) {
+	if rb.root == nil {
+		return *new(T), false
+	}
+	return rb.root.maxSubNode().val, true
+}
+
+// Range 按照从小到大的顺序遍历区间 lo ~ hi 内的元素
+//
+// loInclusive、hiInclusive 分别表示是否包含左右端点；
+// 先定位起点，再沿着后继节点遍历，复杂度 O(log n + k)。遍历过程中不能修改红黑树
+func (rb *RBTree[T]) Range(lo, hi T, loInclusive, hiInclusive bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var start *rbNode[T]
+		if loInclusive {
+			start = rb.lowerBound(lo)
+		} else {
+			start = rb.upperBound(lo)
+		}
+		for cur := start; cur != nil; cur = cur.successor() {
+			c := rb.cmp(cur.val, hi)
+			if c > 0 || (c == 0 && !hiInclusive) {
+				return
+			}
+			if !yield(cur.val) {
+				return
+			}
+		}
+	}
+}
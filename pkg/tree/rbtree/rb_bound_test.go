@@ -0,0 +1,82 @@
+package rbtree
+
+import (
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBound(t *testing.T) {
+	rb := NewRBTree[int]()
+	for _, f := range []func(int) (int, bool){rb.Floor, rb.Ceiling, rb.Lower, rb.Higher} {
+		_, ok := f(1)
+		require.False(t, ok)
+	}
+	_, ok := rb.Min()
+	require.False(t, ok)
+	_, ok = rb.Max()
+	require.False(t, ok)
+
+	data := GenUniqList()
+	for idx := range data {
+		rb.Insert(data[idx])
+	}
+	slices.Sort(data)
+
+	minVal, ok := rb.Min()
+	require.True(t, ok)
+	require.Equal(t, data[0], minVal)
+	maxVal, ok := rb.Max()
+	require.True(t, ok)
+	require.Equal(t, data[len(data)-1], maxVal)
+
+	// 与有序切片上的二分查找结果对比
+	for v := -1; v <= 1001; v++ {
+		i := sort.SearchInts(data, v)   // 第一个 >= v
+		j := sort.SearchInts(data, v+1) // 第一个 > v
+
+		got, ok := rb.Ceiling(v)
+		require.Equal(t, i < len(data), ok)
+		if ok {
+			require.Equal(t, data[i], got)
+		}
+		got, ok = rb.Higher(v)
+		require.Equal(t, j < len(data), ok)
+		if ok {
+			require.Equal(t, data[j], got)
+		}
+		got, ok = rb.Floor(v)
+		require.Equal(t, j > 0, ok)
+		if ok {
+			require.Equal(t, data[j-1], got)
+		}
+		got, ok = rb.Lower(v)
+		require.Equal(t, i > 0, ok)
+		if ok {
+			require.Equal(t, data[i-1], got)
+		}
+	}
+}
+
+func TestRange(t *testing.T) {
+	rb := NewRBTree[int]()
+	for _, v := range []int{1, 3, 5, 7, 9} {
+		rb.Insert(v)
+	}
+	require.Equal(t, []int{3, 5, 7}, slices.Collect(rb.Range(3, 7, true, true)))
+	require.Equal(t, []int{5, 7}, slices.Collect(rb.Range(3, 7, false, true)))
+	require.Equal(t, []int{3, 5}, slices.Collect(rb.Range(3, 7, true, false)))
+	require.Equal(t, []int{5}, slices.Collect(rb.Range(3, 7, false, false)))
+	require.Equal(t, []int{3, 5}, slices.Collect(rb.Range(2, 6, false, false)))
+	require.Equal(t, []int{1, 3, 5, 7, 9}, slices.Collect(rb.Range(0, 10, true, true)))
+	require.Empty(t, slices.Collect(rb.Range(7, 3, true, true)))
+	require.Empty(t, slices.Collect(rb.Range(10, 20, true, true)))
+
+	// 提前结束遍历
+	for v := range rb.Range(0, 10, true, true) {
+		require.Equal(t, 1, v)
+		break
+	}
+}
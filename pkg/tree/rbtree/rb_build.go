@@ -0,0 +1,75 @@
+package rbtree
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// ErrNotSorted 表示输入序列不是严格递增的
+var ErrNotSorted = errors.New("values are not strictly sorted")
+
+// FromSorted 由严格递增的有序切片线性构建红黑树，复杂度 O(n)
+func FromSorted[T cmp.Ordered](vals []T) (*RBTree[T], error) {
+	return FromSortedFunc(vals, cmp.Compare[T])
+}
+
+// FromSortedSeq 由严格递增的迭代器线性构建红黑树，复杂度 O(n)
+func FromSortedSeq[T cmp.Ordered](seq iter.Seq[T]) (*RBTree[T], error) {
+	return FromSortedFunc(slices.Collect(seq), cmp.Compare[T])
+}
+
+// FromSortedFunc 使用自定义比较函数，由严格递增的有序切片线性构建红黑树
+func FromSortedFunc[T any](vals []T, cmp func(a, b T) int) (*RBTree[T], error) {
+	rb := NewRBTreeFunc(cmp)
+	if !rb.isSorted(vals) {
+		return nil, ErrNotSorted
+	}
+	rb.root = rb.buildSorted(vals)
+	return rb, nil
+}
+
+// isSorted 判断序列是否有序：多重集合模式下要求非递减，否则要求严格递增
+func (rb *RBTree[T]) isSorted(vals []T) bool {
+	for i := 1; i < len(vals); i++ {
+		c := rb.cmp(vals[i-1], vals[i])
+		if c > 0 || (c == 0 && !rb.multi) {
+			return false
+		}
+	}
+	return true
+}
+
+// buildSorted 由有序序列线性构建红黑树，返回根节点
+//
+// 每次取中点作为根节点递归构建，得到的树所有 NIL 节点的深度最多相差 1；
+// 只把最深一层的节点染成红色，其余节点为黑色，即满足红黑树的性质
+func (rb *RBTree[T]) buildSorted(vals []T) *rbNode[T] {
+	// 最长路径上的节点数量
+	height := bits.Len(uint(len(vals)))
+	return rb.buildSortedRecursive(vals, 0, height)
+}
+
+// buildSortedRecursive 递归构建子树，depth 为子树根节点的深度（根节点为 0）
+func (rb *RBTree[T]) buildSortedRecursive(vals []T, depth, height int) *rbNode[T] {
+	if len(vals) == 0 {
+		return nil
+	}
+	mid := len(vals) >> 1
+	node := rb.newNode(vals[mid], black)
+	if depth > 0 && depth == height-1 {
+		node.color = red
+	}
+	node.left = rb.buildSortedRecursive(vals[:mid], depth+1, height)
+	node.right = rb.buildSortedRecursive(vals[mid+1:], depth+1, height)
+	if node.left != nil {
+		node.left.parent = node
+	}
+	if node.right != nil {
+		node.right.parent = node
+	}
+	node.update(rb.augmenter)
+	return node
+}
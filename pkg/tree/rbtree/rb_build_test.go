@@ -0,0 +1,55 @@
+package rbtree
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromSorted(t *testing.T) {
+	// 覆盖各种长度，包括满二叉树以及非满二叉树
+	for n := range 300 {
+		vals := make([]int, 0, n)
+		for i := range n {
+			vals = append(vals, i*2)
+		}
+		rb, err := FromSorted(vals)
+		require.NoError(t, err)
+		require.True(t, rb.IsValid())
+		require.Equal(t, n, rb.Len())
+		require.Equal(t, vals, append([]int{}, slices.Collect(rb.All())...))
+
+		// 构建后的树仍然可以正常插入删除
+		rb.Insert(-1)
+		rb.Insert(n*2 + 1)
+		if n > 0 {
+			rb.Delete(vals[n/2])
+		}
+		require.True(t, rb.IsValid())
+	}
+
+	data := GenUniqList()
+	slices.Sort(data)
+	rb, err := FromSortedSeq(slices.Values(data))
+	require.NoError(t, err)
+	require.True(t, rb.IsValid())
+	require.Equal(t, data, slices.Collect(rb.All()))
+}
+
+func TestFromSortedNotSorted(t *testing.T) {
+	_, err := FromSorted([]int{1, 3, 2})
+	require.ErrorIs(t, err, ErrNotSorted)
+	_, err = FromSorted([]int{1, 1})
+	require.ErrorIs(t, err, ErrNotSorted)
+	_, err = FromSortedSeq(slices.Values([]string{"b", "a"}))
+	require.ErrorIs(t, err, ErrNotSorted)
+
+	rb, err := FromSortedFunc([]string{"c", "B", "a"}, func(a, b string) int {
+		return -strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	require.NoError(t, err)
+	require.True(t, rb.IsValid())
+	require.NotNil(t, rb.Find("b"))
+}
@@ -0,0 +1,139 @@
+package rbtree
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// 红黑树被破坏的规则，可以通过 errors.Is 判断 Check 返回的错误
+var (
+	ErrRootColor    = errors.New("root is not black")
+	ErrRedViolation = errors.New("red node has red child")
+	ErrBlackHeight  = errors.New("black height mismatch")
+	ErrOrder        = errors.New("bst order violated")
+	ErrParent       = errors.New("parent pointer inconsistent")
+	ErrSize         = errors.New("subtree size mismatch")
+)
+
+// InvariantError 红黑树性质被破坏时的详细信息
+type InvariantError struct {
+	Rule  error  // 被破坏的规则
+	Value any    // 出错节点的值
+	Path  string // 从根节点到出错节点的路径，如 root/L/R
+}
+
+// Error 实现 error
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("rbtree: %v at %s (value=%v)", e.Rule, e.Path, e.Value)
+}
+
+// Unwrap 返回被破坏的规则
+func (e *InvariantError) Unwrap() error {
+	return e.Rule
+}
+
+// Check 验证红黑树所有性质，返回第一个被破坏的规则以及出错的位置：
+//
+// 1. 根节点为黑色
+// 2. 红色节点不能连续出现
+// 3. 所有路径黑高一致
+// 4. 满足二叉搜索树的有序性（多重集合模式下允许相等）
+// 5. parent 指针与孩子指针一致
+// 6. 子树大小记录正确
+func (rb *RBTree[T]) Check() error {
+	if rb.root == nil {
+		return nil
+	}
+	path := []string{"root"}
+	if rb.root.color != black {
+		return newInvariantError(ErrRootColor, rb.root, path)
+	}
+	if rb.root.parent != nil {
+		return newInvariantError(ErrParent, rb.root, path)
+	}
+	_, err := rb.check(rb.root, path, nil, nil)
+	return err
+}
+
+// SetDebug 开启或关闭调试模式
+//
+// 插入删除只会因为内部实现的错误破坏红黑树的性质，正常情况下不会返回错误；
+// 调试模式下每次修改后都会执行 Check（O(n)），发现内部错误或者性质被破坏时 panic，
+// panic 的值为对应的 error，适用于测试以及排查问题
+func (rb *RBTree[T]) SetDebug(debug bool) {
+	rb.debug = debug
+}
+
+// validate 调试模式下校验修改的结果，err 为修复过程中产生的内部错误
+func (rb *RBTree[T]) validate(err error) {
+	if !rb.debug {
+		return
+	}
+	if err == nil {
+		err = rb.Check()
+	}
+	if err != nil {
+		panic(err)
+	}
+}
+
+// newInvariantError 构建 InvariantError
+func newInvariantError[T any](rule error, node *rbNode[T], path []string) *InvariantError {
+	return &InvariantError{
+		Rule:  rule,
+		Value: node.val,
+		Path:  strings.Join(path, "/"),
+	}
+}
+
+// check 递归验证子树，lo、hi 为子树中元素的边界（nil 表示无边界）
+//
+// 返回子树的黑高（NIL 节点视为黑色，黑高为 1）
+func (rb *RBTree[T]) check(node *rbNode[T], path []string, lo, hi *T) (int, error) {
+	if node == nil {
+		return 1, nil
+	}
+	if (lo != nil && !rb.inOrder(*lo, node.val)) || (hi != nil && !rb.inOrder(node.val, *hi)) {
+		return 0, newInvariantError(ErrOrder, node, path)
+	}
+
+	bh := [2]int{}
+	for i, child := range [2]*rbNode[T]{node.left, node.right} {
+		// 左右孩子复用同一段底层数组，出错时会立即拼接成字符串，不受影响
+		childPath := append(path, [2]string{"L", "R"}[i])
+		if child != nil {
+			if child.parent != node {
+				return 0, newInvariantError(ErrParent, child, childPath)
+			}
+			if node.color == red && child.color == red {
+				return 0, newInvariantError(ErrRedViolation, child, childPath)
+			}
+		}
+		childLo, childHi := lo, &node.val
+		if i == 1 {
+			childLo, childHi = &node.val, hi
+		}
+		h, err := rb.check(child, childPath, childLo, childHi)
+		if err != nil {
+			return 0, err
+		}
+		bh[i] = h
+	}
+	if bh[0] != bh[1] {
+		return 0, newInvariantError(ErrBlackHeight, node, path)
+	}
+	if node.size != 1+sizeOf(node.left)+sizeOf(node.right) {
+		return 0, newInvariantError(ErrSize, node, path)
+	}
+	if node.color == black {
+		return bh[0] + 1, nil
+	}
+	return bh[0], nil
+}
+
+// inOrder a 是否可以排在 b 之前：非多重集合模式下要求严格小于
+func (rb *RBTree[T]) inOrder(a, b T) bool {
+	c := rb.cmp(a, b)
+	return c < 0 || (c == 0 && rb.multi)
+}
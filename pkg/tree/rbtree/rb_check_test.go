@@ -0,0 +1,110 @@
+package rbtree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// buildCheckTree 构建一棵合法的红黑树
+//
+//	     4(B)
+//	    /    \
+//	 2(R)    6(B)
+//	 /  \
+//	1(B) 3(B)
+func buildCheckTree() (*RBTree[int], map[int]*rbNode[int]) {
+	rb := NewRBTree[int]()
+	nodes := map[int]*rbNode[int]{}
+	for _, v := range []int{4, 2, 6, 1, 3} {
+		nodes[v] = newRBNode(v, black)
+	}
+	nodes[2].color = red
+	link := func(p, l, r int) {
+		nodes[p].left, nodes[p].right = nodes[l], nodes[r]
+		nodes[l].parent, nodes[r].parent = nodes[p], nodes[p]
+	}
+	link(4, 2, 6)
+	link(2, 1, 3)
+	nodes[2].size, nodes[4].size = 3, 5
+	rb.root = nodes[4]
+	return rb, nodes
+}
+
+func TestCheck(t *testing.T) {
+	rb, _ := buildCheckTree()
+	require.NoError(t, rb.Check())
+	require.NoError(t, NewRBTree[int]().Check())
+
+	cases := []struct {
+		name    string
+		corrupt func(rb *RBTree[int], nodes map[int]*rbNode[int])
+		rule    error
+		value   int
+		path    string
+	}{
+		{"RootColor", func(rb *RBTree[int], n map[int]*rbNode[int]) {
+			n[4].color = red
+		}, ErrRootColor, 4, "root"},
+		{"RedViolation", func(rb *RBTree[int], n map[int]*rbNode[int]) {
+			n[3].color = red
+		}, ErrRedViolation, 3, "root/L/R"},
+		{"BlackHeight", func(rb *RBTree[int], n map[int]*rbNode[int]) {
+			n[6].color = red
+		}, ErrBlackHeight, 4, "root"},
+		{"Order", func(rb *RBTree[int], n map[int]*rbNode[int]) {
+			n[3].val = 5
+		}, ErrOrder, 5, "root/L/R"},
+		{"Parent", func(rb *RBTree[int], n map[int]*rbNode[int]) {
+			n[1].parent = n[4]
+		}, ErrParent, 1, "root/L/L"},
+		{"Size", func(rb *RBTree[int], n map[int]*rbNode[int]) {
+			n[2].size = 2
+		}, ErrSize, 2, "root/L"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			rb, nodes := buildCheckTree()
+			c.corrupt(rb, nodes)
+			err := rb.Check()
+			require.ErrorIs(t, err, c.rule)
+			require.False(t, rb.IsValid())
+
+			var ie *InvariantError
+			require.True(t, errors.As(err, &ie))
+			require.Equal(t, c.value, ie.Value)
+			require.Equal(t, c.path, ie.Path)
+			require.Contains(t, err.Error(), c.path)
+		})
+	}
+}
+
+func TestDebug(t *testing.T) {
+	rb := NewRBTree[int]()
+	rb.SetDebug(true)
+	data := GenUniqList()
+	require.NotPanics(t, func() {
+		for idx := range data {
+			rb.Insert(data[idx])
+		}
+		for idx := range data[:len(data)/2] {
+			rb.Delete(data[idx])
+		}
+	})
+
+	// 人为破坏红黑树后，下一次修改会 panic
+	rb, nodes := buildCheckTree()
+	nodes[6].color = red
+	rb.SetDebug(true)
+	require.PanicsWithError(t, rb.Check().Error(), func() {
+		rb.Insert(0)
+	})
+
+	// 关闭调试模式后不再校验
+	rb, nodes = buildCheckTree()
+	nodes[6].color = red
+	require.NotPanics(t, func() {
+		rb.Insert(0)
+	})
+}
@@ -0,0 +1,219 @@
+package rbtree
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+var (
+	// ErrNoCodec 表示没有设置元素的编解码器
+	ErrNoCodec = errors.New("codec is not set")
+	// ErrCorrupted 表示序列化的数据不合法
+	ErrCorrupted = errors.New("corrupted data")
+	// ErrNoCompare 表示没有设置比较函数，并且元素不是内置的有序类型
+	ErrNoCompare = errors.New("compare function is not set")
+)
+
+// Codec 元素的二进制编解码器
+type Codec[T any] interface {
+	// Append 将 val 编码后追加到 dst 末尾，返回新的切片
+	Append(dst []byte, val T) []byte
+	// Decode 从 src 头部解码一个元素，返回元素以及消耗的字节数
+	Decode(src []byte) (T, int, error)
+}
+
+// Integer 有符号整数
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64
+}
+
+// Unsigned 无符号整数
+type Unsigned interface {
+	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// IntCodec 有符号整数的编解码器（zigzag 变长编码）
+type IntCodec[T Integer] struct{}
+
+// Append 实现 Codec
+func (IntCodec[T]) Append(dst []byte, val T) []byte {
+	return binary.AppendVarint(dst, int64(val))
+}
+
+// Decode 实现 Codec
+func (IntCodec[T]) Decode(src []byte) (T, int, error) {
+	v, n := binary.Varint(src)
+	// 超出 T 的范围时转换会截断
+	if n <= 0 || int64(T(v)) != v {
+		return 0, 0, ErrCorrupted
+	}
+	return T(v), n, nil
+}
+
+// UintCodec 无符号整数的编解码器（变长编码）
+type UintCodec[T Unsigned] struct{}
+
+// Append 实现 Codec
+func (UintCodec[T]) Append(dst []byte, val T) []byte {
+	return binary.AppendUvarint(dst, uint64(val))
+}
+
+// Decode 实现 Codec
+func (UintCodec[T]) Decode(src []byte) (T, int, error) {
+	v, n := binary.Uvarint(src)
+	if n <= 0 || uint64(T(v)) != v {
+		return 0, 0, ErrCorrupted
+	}
+	return T(v), n, nil
+}
+
+// StringCodec 字符串的编解码器（长度 + 内容）
+type StringCodec[T ~string] struct{}
+
+// Append 实现 Codec
+func (StringCodec[T]) Append(dst []byte, val T) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(val)))
+	return append(dst, val...)
+}
+
+// Decode 实现 Codec
+func (StringCodec[T]) Decode(src []byte) (T, int, error) {
+	size, n := binary.Uvarint(src)
+	if n <= 0 || uint64(len(src)-n) < size {
+		return "", 0, ErrCorrupted
+	}
+	return T(src[n : n+int(size)]), n + int(size), nil
+}
+
+// SetCodec 设置元素的二进制编解码器，用于 MarshalBinary / UnmarshalBinary
+func (rb *RBTree[T]) SetCodec(codec Codec[T]) {
+	rb.codec = codec
+}
+
+// MarshalBinary 实现 encoding.BinaryMarshaler
+//
+// 格式：元素数量（uvarint） + 按照从小到大的顺序依次编码的元素
+func (rb *RBTree[T]) MarshalBinary() ([]byte, error) {
+	if rb.codec == nil {
+		return nil, ErrNoCodec
+	}
+	data := binary.AppendUvarint(nil, uint64(rb.Len()))
+	for val := range rb.All() {
+		data = rb.codec.Append(data, val)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary 实现 encoding.BinaryUnmarshaler，覆盖当前红黑树的内容
+//
+// 零值的红黑树按照 ensureCmp 补充比较函数；数据本身有序，直接线性构建红黑树，不需要逐个插入
+func (rb *RBTree[T]) UnmarshalBinary(data []byte) error {
+	if err := rb.ensureCmp(); err != nil {
+		return err
+	}
+	if rb.codec == nil {
+		return ErrNoCodec
+	}
+	cnt, n := binary.Uvarint(data)
+	if n <= 0 || cnt > uint64(len(data)) {
+		// 每个元素至少占用一个字节
+		return ErrCorrupted
+	}
+	data = data[n:]
+
+	vals := make([]T, 0, cnt)
+	for range cnt {
+		val, size, err := rb.codec.Decode(data)
+		if err != nil {
+			return err
+		}
+		vals = append(vals, val)
+		data = data[size:]
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrCorrupted, len(data))
+	}
+	if !rb.isSorted(vals) {
+		return fmt.Errorf("%w: %w", ErrCorrupted, ErrNotSorted)
+	}
+	rb.root = rb.buildSorted(vals)
+	return nil
+}
+
+// MarshalJSON 实现 json.Marshaler，输出有序数组
+func (rb *RBTree[T]) MarshalJSON() ([]byte, error) {
+	vals := make([]T, 0, rb.Len())
+	for val := range rb.All() {
+		vals = append(vals, val)
+	}
+	return json.Marshal(vals)
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，覆盖当前红黑树的内容
+//
+// 零值的红黑树（如结构体中的字段）按照 ensureCmp 补充比较函数；数组有序时线性构建；否则先排序（非多重集合模式下去重）再构建
+func (rb *RBTree[T]) UnmarshalJSON(data []byte) error {
+	if err := rb.ensureCmp(); err != nil {
+		return err
+	}
+	var vals []T
+	if err := json.Unmarshal(data, &vals); err != nil {
+		return err
+	}
+	if !rb.isSorted(vals) {
+		slices.SortStableFunc(vals, rb.cmp)
+		if !rb.multi {
+			vals = slices.CompactFunc(vals, func(a, b T) bool {
+				return rb.cmp(a, b) == 0
+			})
+		}
+	}
+	rb.root = rb.buildSorted(vals)
+	return nil
+}
+
+// ensureCmp 零值的红黑树没有比较函数，元素为内置的有序类型时使用 cmp.Compare，否则返回 ErrNoCompare
+func (rb *RBTree[T]) ensureCmp() error {
+	if rb.cmp != nil {
+		return nil
+	}
+	var f any
+	switch any(*new(T)).(type) {
+	case int:
+		f = cmp.Compare[int]
+	case int8:
+		f = cmp.Compare[int8]
+	case int16:
+		f = cmp.Compare[int16]
+	case int32:
+		f = cmp.Compare[int32]
+	case int64:
+		f = cmp.Compare[int64]
+	case uint:
+		f = cmp.Compare[uint]
+	case uint8:
+		f = cmp.Compare[uint8]
+	case uint16:
+		f = cmp.Compare[uint16]
+	case uint32:
+		f = cmp.Compare[uint32]
+	case uint64:
+		f = cmp.Compare[uint64]
+	case uintptr:
+		f = cmp.Compare[uintptr]
+	case float32:
+		f = cmp.Compare[float32]
+	case float64:
+		f = cmp.Compare[float64]
+	case string:
+		f = cmp.Compare[string]
+	default:
+		return ErrNoCompare
+	}
+	rb.cmp = f.(func(a, b T) int)
+	return nil
+}
@@ -0,0 +1,189 @@
+package rbtree
+
+import (
+	"encoding"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*RBTree[int])(nil)
+	_ encoding.BinaryUnmarshaler = (*RBTree[int])(nil)
+	_ json.Marshaler             = (*RBTree[int])(nil)
+	_ json.Unmarshaler           = (*RBTree[int])(nil)
+)
+
+func TestMarshalBinary(t *testing.T) {
+	rb := NewRBTree[int]()
+	_, err := rb.MarshalBinary()
+	require.ErrorIs(t, err, ErrNoCodec)
+	require.ErrorIs(t, rb.UnmarshalBinary([]byte{0}), ErrNoCodec)
+
+	rb.SetCodec(IntCodec[int]{})
+	data := GenUniqList()
+	for _, v := range data {
+		rb.Insert(-v)
+	}
+	buf, err := rb.MarshalBinary()
+	require.NoError(t, err)
+
+	loaded := NewRBTree[int]()
+	loaded.SetCodec(IntCodec[int]{})
+	require.NoError(t, loaded.UnmarshalBinary(buf))
+	require.True(t, loaded.IsValid())
+	require.True(t, rb.Equal(loaded))
+
+	// 空树
+	empty := NewRBTree[int]()
+	empty.SetCodec(IntCodec[int]{})
+	buf, err = empty.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, loaded.UnmarshalBinary(buf))
+	require.Equal(t, 0, loaded.Len())
+}
+
+func TestMarshalBinaryCodec(t *testing.T) {
+	words := NewRBTree[string]()
+	words.SetCodec(StringCodec[string]{})
+	for _, w := range []string{"red", "black", "", "树", "tree"} {
+		words.Insert(w)
+	}
+	buf, err := words.MarshalBinary()
+	require.NoError(t, err)
+	loaded := NewRBTree[string]()
+	loaded.SetCodec(StringCodec[string]{})
+	require.NoError(t, loaded.UnmarshalBinary(buf))
+	require.Equal(t, slices.Collect(words.All()), slices.Collect(loaded.All()))
+
+	ids := NewRBTree[uint64]()
+	ids.SetCodec(UintCodec[uint64]{})
+	for _, v := range []uint64{0, 1, 1 << 40, 1<<64 - 1} {
+		ids.Insert(v)
+	}
+	buf, err = ids.MarshalBinary()
+	require.NoError(t, err)
+	loadedIDs := NewRBTree[uint64]()
+	loadedIDs.SetCodec(UintCodec[uint64]{})
+	require.NoError(t, loadedIDs.UnmarshalBinary(buf))
+	require.True(t, ids.Equal(loadedIDs))
+}
+
+func TestUnmarshalBinaryCorrupted(t *testing.T) {
+	rb := NewRBTree[int]()
+	rb.SetCodec(IntCodec[int]{})
+	require.ErrorIs(t, rb.UnmarshalBinary(nil), ErrCorrupted)
+	// 数量与数据不匹配
+	require.ErrorIs(t, rb.UnmarshalBinary([]byte{3, 2}), ErrCorrupted)
+	// 末尾有多余的数据
+	require.ErrorIs(t, rb.UnmarshalBinary([]byte{1, 2, 4}), ErrCorrupted)
+	// 无序
+	require.ErrorIs(t, rb.UnmarshalBinary([]byte{2, 4, 2}), ErrNotSorted)
+
+	words := NewRBTree[string]()
+	words.SetCodec(StringCodec[string]{})
+	require.ErrorIs(t, words.UnmarshalBinary([]byte{1, 5, 'a'}), ErrCorrupted)
+}
+
+func TestUnmarshalBinaryNarrow(t *testing.T) {
+	// 编码为较宽的类型，解码为较窄的类型时超出范围的元素不能被截断
+	wide := NewRBTree[int]()
+	wide.SetCodec(IntCodec[int]{})
+	wide.Insert(-1)
+	wide.Insert(100)
+	data, err := wide.MarshalBinary()
+	require.NoError(t, err)
+	narrow := NewRBTree[int8]()
+	narrow.SetCodec(IntCodec[int8]{})
+	require.NoError(t, narrow.UnmarshalBinary(data))
+	require.Equal(t, []int8{-1, 100}, slices.Collect(narrow.All()))
+
+	wide.Insert(300)
+	data, err = wide.MarshalBinary()
+	require.NoError(t, err)
+	require.ErrorIs(t, narrow.UnmarshalBinary(data), ErrCorrupted)
+	wide.Delete(300)
+	wide.Insert(-129)
+	data, err = wide.MarshalBinary()
+	require.NoError(t, err)
+	require.ErrorIs(t, narrow.UnmarshalBinary(data), ErrCorrupted)
+
+	ids := NewRBTree[uint64]()
+	ids.SetCodec(UintCodec[uint64]{})
+	ids.Insert(1)
+	ids.Insert(255)
+	data, err = ids.MarshalBinary()
+	require.NoError(t, err)
+	small := NewRBTree[uint8]()
+	small.SetCodec(UintCodec[uint8]{})
+	require.NoError(t, small.UnmarshalBinary(data))
+	require.Equal(t, []uint8{1, 255}, slices.Collect(small.All()))
+
+	// 256 超出 uint8 的范围，1 << 40 超出 uint32 的范围
+	ids.Insert(256)
+	ids.Insert(1 << 40)
+	data, err = ids.MarshalBinary()
+	require.NoError(t, err)
+	require.ErrorIs(t, small.UnmarshalBinary(data), ErrCorrupted)
+	small32 := NewRBTree[uint32]()
+	small32.SetCodec(UintCodec[uint32]{})
+	require.ErrorIs(t, small32.UnmarshalBinary(data), ErrCorrupted)
+}
+
+func TestMarshalJSON(t *testing.T) {
+	rb := NewRBTree[int]()
+	buf, err := json.Marshal(rb)
+	require.NoError(t, err)
+	require.Equal(t, "[]", string(buf))
+
+	for _, v := range []int{3, 1, 2} {
+		rb.Insert(v)
+	}
+	buf, err = json.Marshal(rb)
+	require.NoError(t, err)
+	require.Equal(t, "[1,2,3]", string(buf))
+
+	loaded := NewRBTree[int]()
+	require.NoError(t, json.Unmarshal(buf, loaded))
+	require.True(t, loaded.IsValid())
+	require.True(t, rb.Equal(loaded))
+
+	// 无序、重复的数组也可以加载
+	require.NoError(t, json.Unmarshal([]byte("[5,3,3,9,1]"), loaded))
+	require.True(t, loaded.IsValid())
+	require.Equal(t, []int{1, 3, 5, 9}, slices.Collect(loaded.All()))
+
+	multi := NewRBMultiset[int]()
+	require.NoError(t, json.Unmarshal([]byte("[5,3,3,9,1]"), multi))
+	require.True(t, multi.IsValid())
+	require.Equal(t, []int{1, 3, 3, 5, 9}, slices.Collect(multi.All()))
+	require.Equal(t, 2, multi.Count(3))
+
+	require.Error(t, json.Unmarshal([]byte(`["a"]`), loaded))
+}
+
+func TestUnmarshalZeroValue(t *testing.T) {
+	// 结构体中的零值字段，内置的有序类型使用 cmp.Compare
+	var s struct{ T RBTree[int] }
+	require.NoError(t, json.Unmarshal([]byte(`{"T":[3,1,2]}`), &s))
+	require.True(t, s.T.IsValid())
+	require.Equal(t, []int{1, 2, 3}, slices.Collect(s.T.All()))
+	require.True(t, s.T.Insert(4))
+
+	var p struct{ T *RBTree[string] }
+	require.NoError(t, json.Unmarshal([]byte(`{"T":["b","a","b"]}`), &p))
+	require.Equal(t, []string{"a", "b"}, slices.Collect(p.T.All()))
+
+	var rb RBTree[uint8]
+	rb.SetCodec(UintCodec[uint8]{})
+	require.NoError(t, rb.UnmarshalBinary([]byte{2, 1, 2}))
+	require.Equal(t, []uint8{1, 2}, slices.Collect(rb.All()))
+
+	// 其他类型没有默认的比较函数
+	type point struct{ X, Y int }
+	var pts RBTree[point]
+	require.ErrorIs(t, pts.UnmarshalJSON([]byte(`[{"X":1,"Y":2}]`)), ErrNoCompare)
+	require.ErrorIs(t, pts.UnmarshalBinary([]byte{0}), ErrNoCompare)
+}
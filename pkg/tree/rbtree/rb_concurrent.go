@@ -0,0 +1,124 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// ConcurrentRBTree 并发安全的红黑树
+//
+// 内部使用持久化红黑树保存当前版本：写入通过路径复制得到新版本，复杂度 O(log n)，
+// 写入之间使用互斥锁串行化；读取以及获取快照直接读取当前版本，不需要加锁，
+// 快照即为某个版本本身，获取快照为 O(1)，并且不会增加之后写入的开销
+type ConcurrentRBTree[T any] struct {
+	mu  sync.Mutex
+	cur atomic.Pointer[PersistentRBTree[T]]
+}
+
+// NewConcurrentRBTree 创建并发安全的红黑树
+func NewConcurrentRBTree[T cmp.Ordered]() *ConcurrentRBTree[T] {
+	return NewConcurrentRBTreeFunc(cmp.Compare[T])
+}
+
+// NewConcurrentRBTreeFunc 使用自定义比较函数创建并发安全的红黑树
+func NewConcurrentRBTreeFunc[T any](cmp func(a, b T) int) *ConcurrentRBTree[T] {
+	c := &ConcurrentRBTree[T]{}
+	c.cur.Store(NewPersistentRBTreeFunc(cmp))
+	return c
+}
+
+// Insert 插入，返回是否插入了新元素
+func (c *ConcurrentRBTree[T]) Insert(val T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := c.cur.Load()
+	next := old.Insert(val)
+	c.cur.Store(next)
+	return next != old
+}
+
+// Delete 删除，返回是否删除了元素
+func (c *ConcurrentRBTree[T]) Delete(val T) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	old := c.cur.Load()
+	next := old.Delete(val)
+	c.cur.Store(next)
+	return next != old
+}
+
+// Contains 是否包含 val
+func (c *ConcurrentRBTree[T]) Contains(val T) bool {
+	_, ok := c.cur.Load().Find(val)
+	return ok
+}
+
+// Len 元素数量
+func (c *ConcurrentRBTree[T]) Len() int {
+	return c.cur.Load().Len()
+}
+
+// Snapshot 获取当前时刻的只读快照
+//
+// 快照不受之后写入的影响，可以在写入的同时安全地并发遍历
+func (c *ConcurrentRBTree[T]) Snapshot() *Snapshot[T] {
+	return &Snapshot[T]{tree: c.cur.Load()}
+}
+
+// Snapshot 红黑树的只读快照
+type Snapshot[T any] struct {
+	tree *PersistentRBTree[T]
+}
+
+// Len 元素数量
+func (s *Snapshot[T]) Len() int {
+	return s.tree.Len()
+}
+
+// Contains 是否包含 val
+func (s *Snapshot[T]) Contains(val T) bool {
+	_, ok := s.tree.Find(val)
+	return ok
+}
+
+// All 按照从小到大的顺序遍历
+func (s *Snapshot[T]) All() iter.Seq[T] {
+	return s.tree.All()
+}
+
+// Backward 按照从大到小的顺序遍历
+func (s *Snapshot[T]) Backward() iter.Seq[T] {
+	return s.tree.Backward()
+}
+
+// Range 按照从小到大的顺序遍历区间 lo ~ hi 内的元素
+func (s *Snapshot[T]) Range(lo, hi T, loInclusive, hiInclusive bool) iter.Seq[T] {
+	return s.tree.Range(lo, hi, loInclusive, hiInclusive)
+}
+
+// Floor 小于等于 val 的最大元素
+func (s *Snapshot[T]) Floor(val T) (T, bool) {
+	return s.tree.Floor(val)
+}
+
+// Ceiling 大于等于 val 的最小元素
+func (s *Snapshot[T]) Ceiling(val T) (T, bool) {
+	return s.tree.Ceiling(val)
+}
+
+// Rank 小于 val 的元素数量
+func (s *Snapshot[T]) Rank(val T) int {
+	return s.tree.Rank(val)
+}
+
+// Select 查找第 k 小的元素（k 从 0 开始）
+func (s *Snapshot[T]) Select(k int) (T, bool) {
+	return s.tree.Select(k)
+}
+
+// IsValid 验证红黑树的性质
+func (s *Snapshot[T]) IsValid() bool {
+	return s.tree.IsValid()
+}
@@ -0,0 +1,115 @@
+package rbtree
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentRBTree(t *testing.T) {
+	const workers = 8
+	var (
+		ct = NewConcurrentRBTree[int]()
+		wg sync.WaitGroup
+	)
+
+	// 写入协程（协程中不能调用 t.FailNow，使用 assert）
+	lists := make([][]int, workers)
+	for i := range workers {
+		lists[i] = GenBFSList()
+		wg.Add(1)
+		go func(data []int) {
+			defer wg.Done()
+			for _, v := range data {
+				ct.Insert(v)
+			}
+			for _, v := range data[:len(data)/2] {
+				ct.Delete(v)
+			}
+		}(lists[i])
+	}
+
+	// 读取协程：在写入的同时遍历快照
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				snap := ct.Snapshot()
+				vals := slices.Collect(snap.All())
+				assert.Len(t, vals, snap.Len())
+				assert.True(t, slices.IsSorted(vals))
+				assert.True(t, snap.IsValid())
+				for _, v := range vals {
+					assert.True(t, snap.Contains(v))
+				}
+				if len(vals) > 0 {
+					ct.Contains(vals[0])
+				}
+				_ = ct.Len()
+			}
+		}()
+	}
+	wg.Wait()
+
+	snap := ct.Snapshot()
+	require.True(t, snap.IsValid())
+	require.Equal(t, ct.Len(), snap.Len())
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	ct := NewConcurrentRBTree[int]()
+	data := GenUniqList()
+	for _, v := range data {
+		ct.Insert(v)
+	}
+	snap := ct.Snapshot()
+	before := slices.Collect(snap.All())
+
+	// 快照之后的写入不影响快照
+	for _, v := range data {
+		ct.Delete(v)
+	}
+	ct.Insert(-1)
+	require.Equal(t, 1, ct.Len())
+	require.True(t, ct.Contains(-1))
+
+	require.Equal(t, len(data), snap.Len())
+	require.Equal(t, before, slices.Collect(snap.All()))
+	require.False(t, snap.Contains(-1))
+	require.True(t, snap.IsValid())
+
+	slices.Sort(data)
+	val, ok := snap.Select(0)
+	require.True(t, ok)
+	require.Equal(t, data[0], val)
+	require.Equal(t, 1, snap.Rank(data[1]))
+}
+
+func TestSnapshotWriteCost(t *testing.T) {
+	ct := NewConcurrentRBTree[int]()
+	for i := range 10000 {
+		ct.Insert(i)
+	}
+
+	// 频繁获取快照时，写入只复制查找路径上的节点，不会复制整棵树
+	next := 10000
+	allocs := testing.AllocsPerRun(100, func() {
+		ct.Snapshot()
+		ct.Insert(next)
+		next++
+	})
+	require.Less(t, allocs, 100.0)
+
+	// 重复插入、删除不存在的元素不会产生新的版本
+	snap := ct.Snapshot()
+	allocs = testing.AllocsPerRun(100, func() {
+		ct.Insert(0)
+		ct.Delete(-1)
+	})
+	require.Zero(t, allocs)
+	require.Same(t, snap.tree, ct.Snapshot().tree)
+}
@@ -0,0 +1,104 @@
+package rbtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// 操作类型，由操作字节对 opCount 取模得到
+const (
+	opInsert = iota
+	opDelete
+	opHandleDelete // 通过 Find 返回的句柄删除
+	opCount
+)
+
+// sliceModel 基于有序切片的参考模型
+type sliceModel struct {
+	vals  []int
+	multi bool
+}
+
+// insert 与 RBTree.Insert 语义一致，相等元素插入到最后
+func (m *sliceModel) insert(val int) bool {
+	idx, found := slices.BinarySearch(m.vals, val)
+	if found && !m.multi {
+		return false
+	}
+	for idx < len(m.vals) && m.vals[idx] == val {
+		idx++
+	}
+	m.vals = slices.Insert(m.vals, idx, val)
+	return true
+}
+
+// delete 与 RBTree.Delete 语义一致，多重集合只删除一个
+func (m *sliceModel) delete(val int) bool {
+	idx, found := slices.BinarySearch(m.vals, val)
+	if !found {
+		return false
+	}
+	m.vals = slices.Delete(m.vals, idx, idx+1)
+	return true
+}
+
+// runModel 依次执行 ops，每两个字节为一步：操作类型以及操作的值
+//
+// 值只取一个字节，保证插入删除能频繁命中已有元素；每一步之后校验红黑树性质并与参考模型比对
+func runModel(t *testing.T, multi bool, ops []byte) {
+	rb := NewRBTree[int]()
+	if multi {
+		rb = NewRBMultiset[int]()
+	}
+	model := &sliceModel{vals: []int{}, multi: multi}
+	for step := 0; step+1 < len(ops); step += 2 {
+		val := int(int8(ops[step+1]))
+		switch ops[step] % opCount {
+		case opInsert:
+			require.Equal(t, model.insert(val), rb.Insert(val), "step %d: Insert(%d)", step/2, val)
+		case opDelete:
+			require.Equal(t, model.delete(val), rb.Delete(val), "step %d: Delete(%d)", step/2, val)
+		case opHandleDelete:
+			h := rb.Find(val)
+			require.Equal(t, model.delete(val), h != nil && h.Delete(), "step %d: Find(%d).Delete()", step/2, val)
+		}
+		require.NoError(t, rb.Check(), "step %d", step/2)
+		require.Equal(t, len(model.vals), rb.Len(), "step %d", step/2)
+		require.Equal(t, model.vals, slices.AppendSeq([]int{}, rb.All()), "step %d", step/2)
+	}
+}
+
+// genOps 根据种子生成 n 步操作，相同的种子总是得到相同的操作序列
+func genOps(seed int64, n int) []byte {
+	rd := rand.New(rand.NewSource(seed))
+	ops := make([]byte, 2*n)
+	rd.Read(ops)
+	return ops
+}
+
+func FuzzRBTree(f *testing.F) {
+	f.Add(false, []byte{})
+	f.Add(false, []byte{opInsert, 1, opInsert, 1, opDelete, 1, opDelete, 1})
+	f.Add(true, []byte{opInsert, 1, opInsert, 1, opHandleDelete, 1, opDelete, 1, opDelete, 1})
+	for seed := range int64(8) {
+		f.Add(seed%2 == 1, genOps(seed, 256))
+	}
+	f.Fuzz(func(t *testing.T, multi bool, ops []byte) {
+		runModel(t, multi, ops)
+	})
+}
+
+// FuzzRBTreeSeed 通过种子生成随机操作序列，失败时可以使用相同的种子复现
+func FuzzRBTreeSeed(f *testing.F) {
+	for seed := range int64(8) {
+		f.Add(seed, uint16(512))
+	}
+	f.Fuzz(func(t *testing.T, seed int64, n uint16) {
+		t.Logf("seed: %d, steps: %d", seed, n)
+		runModel(t, false, genOps(seed, int(n)))
+		runModel(t, true, genOps(seed, int(n)))
+	})
+}
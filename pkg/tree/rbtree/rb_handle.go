@@ -0,0 +1,72 @@
+package rbtree
+
+// Handle 指向红黑树中某个元素的句柄
+//
+// 句柄直接持有节点，Next、Prev 沿着 parent 指针移动，Delete 原地删除节点，都不需要再次查找。
+// 插入删除其他元素不会影响已有的句柄；句柄对应的元素被删除后（无论通过哪个句柄或者 Delete）句柄失效，
+// 失效的句柄 Value 返回零值，Next、Prev 返回 nil，Delete 返回 false；
+// Split、Join 以及集合运算等重建红黑树的操作之后原有的句柄同样不能再用于删除
+type Handle[T any] struct {
+	tree *RBTree[T]
+	node *rbNode[T]
+	gen  uint32 // 创建句柄时节点的 gen，与节点当前的 gen 不同说明节点已经被删除
+}
+
+// handleOf 创建节点的句柄，节点为 nil 时返回 nil
+func (rb *RBTree[T]) handleOf(node *rbNode[T]) *Handle[T] {
+	if node == nil {
+		return nil
+	}
+	return &Handle[T]{tree: rb, node: node, gen: node.gen}
+}
+
+// alive 句柄对应的节点是否仍然在树上
+//
+// 节点被删除时 gen 递增，arena 复用节点时也会保留 gen，所以旧的句柄不会指向新的元素
+func (h *Handle[T]) alive() bool {
+	return h.node != nil && h.node.gen == h.gen
+}
+
+// Value 句柄对应的值，句柄已失效时返回零值
+func (h *Handle[T]) Value() T {
+	if !h.alive() {
+		return *new(T)
+	}
+	return h.node.val
+}
+
+// Next 后继元素的句柄，不存在或者句柄已失效时返回 nil
+func (h *Handle[T]) Next() *Handle[T] {
+	if !h.alive() {
+		return nil
+	}
+	return h.tree.handleOf(h.node.successor())
+}
+
+// Prev 前驱元素的句柄，不存在或者句柄已失效时返回 nil
+func (h *Handle[T]) Prev() *Handle[T] {
+	if !h.alive() {
+		return nil
+	}
+	return h.tree.handleOf(h.node.predecessor())
+}
+
+// Delete 从红黑树中删除句柄对应的元素，返回是否删除成功
+//
+// 删除后句柄失效，再次调用返回 false；指向同一元素的其他句柄同样失效。
+// 节点已经通过 Split、Join 移动到其他树上时不会删除，返回 false
+func (h *Handle[T]) Delete() bool {
+	if !h.alive() || h.tree.root == nil {
+		return false
+	}
+	root := h.node
+	for root.parent != nil {
+		root = root.parent
+	}
+	if root != h.tree.root {
+		return false
+	}
+	h.tree.deleteNode(h.node)
+	h.node = nil
+	return true
+}
@@ -0,0 +1,141 @@
+package rbtree
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandle(t *testing.T) {
+	rb := NewRBTree[int]()
+	require.Nil(t, rb.Find(1))
+
+	data := GenUniqList()
+	for idx := range data {
+		rb.Insert(data[idx])
+	}
+	slices.Sort(data)
+
+	// 沿着句柄正向、反向遍历
+	res := make([]int, 0, len(data))
+	for h := rb.Find(data[0]); h != nil; h = h.Next() {
+		res = append(res, h.Value())
+	}
+	require.Equal(t, data, res)
+
+	res = res[:0]
+	for h := rb.Find(data[len(data)-1]); h != nil; h = h.Prev() {
+		res = append(res, h.Value())
+	}
+	slices.Reverse(res)
+	require.Equal(t, data, res)
+}
+
+func TestHandleDelete(t *testing.T) {
+	rb := newSumTree()
+	rb.UseArena(16)
+	data := GenUniqList()
+	for idx := range data {
+		rb.Insert(sumItem{val: data[idx]})
+	}
+
+	// 先拿到所有句柄，再通过句柄删除一半元素，剩余的句柄仍然有效
+	handles := make([]*Handle[sumItem], len(data))
+	for idx := range data {
+		handles[idx] = rb.Find(sumItem{val: data[idx]})
+		require.Equal(t, data[idx], handles[idx].Value().val)
+	}
+	delCnt := len(data) / 2
+	for idx := range handles[:delCnt] {
+		require.True(t, handles[idx].Delete())
+		require.False(t, handles[idx].Delete())
+		require.Zero(t, handles[idx].Value())
+		require.Nil(t, handles[idx].Next())
+		require.Nil(t, handles[idx].Prev())
+		require.Nil(t, rb.Find(sumItem{val: data[idx]}))
+		require.True(t, rb.IsValid())
+	}
+	require.Equal(t, len(data)-delCnt, rb.Len())
+	checkSubtreeSum(t, rb.Root())
+
+	for idx := delCnt; idx < len(data); idx++ {
+		require.Equal(t, data[idx], handles[idx].Value().val)
+	}
+
+	// 删除过程中沿着句柄移动
+	for h := rb.Find(rb.root.minSubNode().val); h != nil; {
+		next := h.Next()
+		require.True(t, h.Delete())
+		h = next
+	}
+	require.Equal(t, 0, rb.Len())
+}
+
+func TestHandleMultiset(t *testing.T) {
+	rb := NewRBMultiset[int]()
+	for range 3 {
+		rb.Insert(1)
+		rb.Insert(2)
+	}
+	h := rb.Find(1)
+	for range 3 {
+		require.Equal(t, 1, h.Value())
+		h = h.Next()
+	}
+	require.Equal(t, 2, h.Value())
+	require.True(t, rb.Find(2).Delete())
+	require.Equal(t, 2, rb.Count(2))
+	require.True(t, rb.IsValid())
+}
+
+func TestHandleStale(t *testing.T) {
+	for _, useArena := range []bool{false, true} {
+		rb := NewRBTree[int]()
+		if useArena {
+			rb.UseArena(4)
+		}
+		for v := range 100 {
+			rb.Insert(v)
+		}
+
+		// 两个句柄指向同一个元素，通过其中一个删除后另一个同样失效
+		h1, h2 := rb.Find(50), rb.Find(50)
+		require.True(t, h1.Delete())
+		require.False(t, h2.Delete())
+		require.Zero(t, h2.Value())
+		require.Nil(t, h2.Next())
+		require.Nil(t, h2.Prev())
+		require.Equal(t, 99, rb.Len())
+		require.True(t, rb.IsValid())
+
+		// 通过值删除同样会使句柄失效
+		h3 := rb.Find(60)
+		require.True(t, rb.Delete(60))
+		require.False(t, h3.Delete())
+
+		// 节点被 arena 复用后，旧的句柄不会指向新的元素
+		require.True(t, rb.Insert(1000))
+		require.True(t, rb.Insert(1001))
+		if useArena {
+			reused := rb.find(1000)
+			require.True(t, reused == h2.node || reused == h3.node)
+		}
+		require.False(t, h2.Delete())
+		require.False(t, h3.Delete())
+		require.Zero(t, h2.Value())
+		require.Zero(t, h3.Value())
+		require.Equal(t, 100, rb.Len())
+		require.NotNil(t, rb.Find(1000))
+		require.NotNil(t, rb.Find(1001))
+		require.True(t, rb.IsValid())
+
+		// 拆分后节点属于新的树，旧树上的句柄不能再删除
+		h4 := rb.Find(10)
+		left, right := rb.Split(20)
+		require.False(t, h4.Delete())
+		require.True(t, left.IsValid())
+		require.True(t, right.IsValid())
+		require.Equal(t, 100, left.Len()+right.Len())
+	}
+}
@@ -0,0 +1,179 @@
+package rbtree
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+)
+
+// ErrInvalidInterval 表示区间不合法（Start >= End）
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// Interval 左闭右开区间 [Start, End)
+type Interval[T cmp.Ordered] struct {
+	Start, End T
+}
+
+// Overlaps 判断两个区间是否有交集
+func (iv Interval[T]) Overlaps(other Interval[T]) bool {
+	return iv.Start < other.End && other.Start < iv.End
+}
+
+// ivItem 区间树中的元素，maxEnd 为子树中所有区间的最大右端点（增强字段，不参与比较）
+type ivItem[T cmp.Ordered] struct {
+	iv     Interval[T]
+	maxEnd T
+}
+
+// compareIvItem 只比较区间的左端点
+func compareIvItem[T cmp.Ordered](a, b ivItem[T]) int {
+	return cmp.Compare(a.iv.Start, b.iv.Start)
+}
+
+// maxEndAugmenter 维护子树中所有区间的最大右端点
+type maxEndAugmenter[T cmp.Ordered] struct{}
+
+// Recompute 实现 Augmenter
+func (maxEndAugmenter[T]) Recompute(val *ivItem[T], left, right *ivItem[T]) {
+	val.maxEnd = val.iv.End
+	if left != nil {
+		val.maxEnd = max(val.maxEnd, left.maxEnd)
+	}
+	if right != nil {
+		val.maxEnd = max(val.maxEnd, right.maxEnd)
+	}
+}
+
+// IntervalTree 区间树
+//
+// 以区间左端点为 key 的多重集合红黑树，每个元素额外维护子树中的最大右端点，
+// 插入删除以及旋转复用 RBTree 的逻辑；左端点相同的区间按照插入顺序排列，重复的区间会全部保留
+type IntervalTree[T cmp.Ordered] struct {
+	tree *RBTree[ivItem[T]]
+}
+
+// NewIntervalTree 创建区间树
+func NewIntervalTree[T cmp.Ordered]() *IntervalTree[T] {
+	tree := NewRBTreeAugmented(compareIvItem[T], maxEndAugmenter[T]{})
+	tree.multi = true
+	return &IntervalTree[T]{tree: tree}
+}
+
+// Len 区间数量
+func (it *IntervalTree[T]) Len() int {
+	return it.tree.Len()
+}
+
+// IsValid 验证底层红黑树的性质
+func (it *IntervalTree[T]) IsValid() bool {
+	return it.tree.IsValid()
+}
+
+// Insert 插入区间，重复的区间同样会插入
+//
+// 区间不合法时返回 ErrInvalidInterval
+func (it *IntervalTree[T]) Insert(iv Interval[T]) error {
+	if iv.Start >= iv.End {
+		return ErrInvalidInterval
+	}
+	it.tree.Insert(ivItem[T]{iv: iv})
+	return nil
+}
+
+// Delete 删除一个与 iv 相同的区间，返回是否删除了区间
+//
+// 从左端点相同的第一个区间开始依次查找右端点相同的区间，复杂度 O(log n + k)，k 为左端点相同的区间数量
+func (it *IntervalTree[T]) Delete(iv Interval[T]) bool {
+	node := it.tree.lowerBound(ivItem[T]{iv: iv})
+	for ; node != nil && node.val.iv.Start == iv.Start; node = node.successor() {
+		if node.val.iv.End == iv.End {
+			it.tree.deleteNode(node)
+			return true
+		}
+	}
+	return false
+}
+
+// All 按照左端点从小到大遍历所有区间
+func (it *IntervalTree[T]) All() iter.Seq[Interval[T]] {
+	return func(yield func(Interval[T]) bool) {
+		for item := range it.tree.All() {
+			if !yield(item.iv) {
+				return
+			}
+		}
+	}
+}
+
+// AnyOverlap 查找任意一个与 [a, b) 有交集的区间，O(log n)
+func (it *IntervalTree[T]) AnyOverlap(a, b T) (Interval[T], bool) {
+	if a >= b {
+		return Interval[T]{}, false
+	}
+	var (
+		target = Interval[T]{Start: a, End: b}
+		cur    = it.tree.root
+	)
+	for cur != nil {
+		if cur.val.iv.Overlaps(target) {
+			return cur.val.iv, true
+		}
+		// 左子树的最大右端点大于 a 时，若左子树没有交集，右子树也一定没有
+		if cur.left != nil && cur.left.val.maxEnd > a {
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+	return Interval[T]{}, false
+}
+
+// Overlapping 查找所有与 [a, b) 有交集的区间，结果按照左端点从小到大排列
+//
+// 复杂度 O(k·log n)，k 为结果数量
+func (it *IntervalTree[T]) Overlapping(a, b T) []Interval[T] {
+	if a >= b {
+		return nil
+	}
+	var (
+		target = Interval[T]{Start: a, End: b}
+		res    []Interval[T]
+	)
+	it.search(it.tree.root, func(iv Interval[T]) bool {
+		return iv.Start < b
+	}, a, func(iv Interval[T]) {
+		if iv.Overlaps(target) {
+			res = append(res, iv)
+		}
+	})
+	return res
+}
+
+// Stabbing 查找所有包含 point 的区间，结果按照左端点从小到大排列
+func (it *IntervalTree[T]) Stabbing(point T) []Interval[T] {
+	var res []Interval[T]
+	it.search(it.tree.root, func(iv Interval[T]) bool {
+		return iv.Start <= point
+	}, point, func(iv Interval[T]) {
+		if iv.Start <= point && point < iv.End {
+			res = append(res, iv)
+		}
+	})
+	return res
+}
+
+// search 中序遍历并剪枝
+//
+// 子树的最大右端点不大于 lo 时，整棵子树都不可能命中；
+// startOK 返回 false 时，右子树的左端点只会更大，也不可能命中
+func (it *IntervalTree[T]) search(node *rbNode[ivItem[T]], startOK func(Interval[T]) bool, lo T, visit func(Interval[T])) {
+	if node == nil || node.val.maxEnd <= lo {
+		return
+	}
+	it.search(node.left, startOK, lo, visit)
+	if !startOK(node.val.iv) {
+		return
+	}
+	visit(node.val.iv)
+	it.search(node.right, startOK, lo, visit)
+}
@@ -0,0 +1,130 @@
+package rbtree
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// genIntervals 生成随机区间
+func genIntervals(rd *rand.Rand, cnt int) []Interval[int] {
+	res := make([]Interval[int], 0, cnt)
+	for range cnt {
+		start := rd.Intn(1000)
+		res = append(res, Interval[int]{Start: start, End: start + rd.Intn(50) + 1})
+	}
+	return res
+}
+
+func TestIntervalTree(t *testing.T) {
+	it := NewIntervalTree[int]()
+	require.ErrorIs(t, it.Insert(Interval[int]{Start: 2, End: 2}), ErrInvalidInterval)
+	require.ErrorIs(t, it.Insert(Interval[int]{Start: 3, End: 2}), ErrInvalidInterval)
+	_, ok := it.AnyOverlap(0, 10)
+	require.False(t, ok)
+	require.Empty(t, it.Overlapping(0, 10))
+	require.Empty(t, it.Stabbing(1))
+
+	for _, iv := range []Interval[int]{{1, 3}, {2, 6}, {8, 9}, {15, 23}, {16, 21}, {17, 19}, {19, 20}, {25, 30}, {26, 27}} {
+		require.NoError(t, it.Insert(iv))
+	}
+	require.True(t, it.IsValid())
+	require.Equal(t, 9, it.Len())
+
+	// 重复的区间都会保留，删除时只删除一个
+	require.NoError(t, it.Insert(Interval[int]{1, 3}))
+	require.Equal(t, 10, it.Len())
+	require.Equal(t, []Interval[int]{{1, 3}, {1, 3}, {2, 6}}, it.Overlapping(0, 3))
+	require.True(t, it.Delete(Interval[int]{1, 3}))
+	require.Equal(t, 9, it.Len())
+
+	require.Equal(t, []Interval[int]{{1, 3}, {2, 6}}, it.Overlapping(0, 3))
+	require.Equal(t, []Interval[int]{{2, 6}}, it.Overlapping(3, 8))
+	require.Empty(t, it.Overlapping(6, 8))
+	require.Empty(t, it.Overlapping(9, 15))
+	require.Equal(t, []Interval[int]{{15, 23}, {16, 21}, {19, 20}}, it.Stabbing(19))
+	require.Empty(t, it.Stabbing(23))
+	require.Equal(t, []Interval[int]{{8, 9}}, it.Stabbing(8))
+
+	iv, ok := it.AnyOverlap(22, 26)
+	require.True(t, ok)
+	require.True(t, iv.Overlaps(Interval[int]{22, 26}))
+	_, ok = it.AnyOverlap(23, 25)
+	require.False(t, ok)
+
+	require.True(t, it.Delete(Interval[int]{15, 23}))
+	require.False(t, it.Delete(Interval[int]{15, 23}))
+	require.Equal(t, []Interval[int]{{16, 21}, {19, 20}}, it.Stabbing(19))
+	require.True(t, it.IsValid())
+
+	// 左端点相同的区间按照插入顺序排列，删除时只匹配右端点相同的区间
+	it = NewIntervalTree[int]()
+	for _, iv := range []Interval[int]{{1, 5}, {1, 3}, {1, 5}, {1, 4}} {
+		require.NoError(t, it.Insert(iv))
+	}
+	require.False(t, it.Delete(Interval[int]{1, 6}))
+	require.True(t, it.Delete(Interval[int]{1, 5}))
+	require.Equal(t, []Interval[int]{{1, 3}, {1, 5}, {1, 4}}, slices.Collect(it.All()))
+	require.Equal(t, []Interval[int]{{1, 3}, {1, 5}, {1, 4}}, it.Stabbing(2))
+	require.Equal(t, []Interval[int]{{1, 5}}, it.Stabbing(4))
+	require.True(t, it.IsValid())
+}
+
+func TestIntervalTreeRandom(t *testing.T) {
+	rd := rand.New(rand.NewSource(int64(time.Now().UnixNano())))
+	for range 100 {
+		var (
+			it   = NewIntervalTree[int]()
+			data = genIntervals(rd, rd.Intn(500)+1)
+		)
+		for _, iv := range data {
+			require.NoError(t, it.Insert(iv))
+		}
+		for _, iv := range data[:len(data)/3] {
+			require.True(t, it.Delete(iv))
+		}
+		require.True(t, it.IsValid())
+		require.Equal(t, len(data)-len(data)/3, it.Len())
+		all := slices.Collect(it.All())
+
+		// 与参考结果比较时不关心左端点相同的区间之间的顺序
+		remain := slices.Clone(data[len(data)/3:])
+		sortIntervals := func(ivs []Interval[int]) {
+			slices.SortFunc(ivs, func(a, b Interval[int]) int {
+				return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.End, b.End))
+			})
+		}
+		sortIntervals(remain)
+		sorted := slices.Clone(all)
+		sortIntervals(sorted)
+		require.Equal(t, remain, sorted)
+
+		for range 20 {
+			a := rd.Intn(1100)
+			b := a + rd.Intn(30) + 1
+			target := Interval[int]{Start: a, End: b}
+
+			var want, stab []Interval[int]
+			for _, iv := range all {
+				if iv.Overlaps(target) {
+					want = append(want, iv)
+				}
+				if iv.Start <= a && a < iv.End {
+					stab = append(stab, iv)
+				}
+			}
+			require.Equal(t, want, it.Overlapping(a, b))
+			require.Equal(t, stab, it.Stabbing(a))
+
+			iv, ok := it.AnyOverlap(a, b)
+			require.Equal(t, len(want) > 0, ok)
+			if ok {
+				require.True(t, iv.Overlaps(target))
+			}
+		}
+	}
+}
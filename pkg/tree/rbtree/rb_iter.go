@@ -0,0 +1,89 @@
+package rbtree
+
+import "iter"
+
+// All 按照从小到大的顺序遍历红黑树
+//
+// 遍历过程中不能修改红黑树
+func (rb *RBTree[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if rb.root == nil {
+			return
+		}
+		for cur := rb.root.minSubNode(); cur != nil; cur = cur.successor() {
+			if !yield(cur.val) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 按照从大到小的顺序遍历红黑树
+//
+// 遍历过程中不能修改红黑树
+func (rb *RBTree[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if rb.root == nil {
+			return
+		}
+		for cur := rb.root.maxSubNode(); cur != nil; cur = cur.predecessor() {
+			if !yield(cur.val) {
+				return
+			}
+		}
+	}
+}
+
+// Cursor 红黑树的双向游标
+//
+// 游标要么指向某个节点，要么位于末尾（node 为 nil）。
+// 末尾可以看作首尾相接的哨兵：从末尾 Next 回到最小节点，Prev 回到最大节点。
+// 游标沿着 parent 指针移动，不需要额外的栈；使用游标期间不能修改红黑树
+type Cursor[T any] struct {
+	tree *RBTree[T]
+	node *rbNode[T]
+}
+
+// Cursor 创建游标，初始位于末尾
+func (rb *RBTree[T]) Cursor() *Cursor[T] {
+	return &Cursor[T]{tree: rb}
+}
+
+// Valid 游标是否指向某个节点
+func (c *Cursor[T]) Valid() bool {
+	return c.node != nil
+}
+
+// Value 游标指向的值，游标位于末尾时返回零值
+func (c *Cursor[T]) Value() T {
+	if c.node == nil {
+		return *new(T)
+	}
+	return c.node.val
+}
+
+// Next 移动到后继节点，返回移动后游标是否有效
+func (c *Cursor[T]) Next() bool {
+	if c.node != nil {
+		c.node = c.node.successor()
+	} else if c.tree.root != nil {
+		c.node = c.tree.root.minSubNode()
+	}
+	return c.node != nil
+}
+
+// Prev 移动到前驱节点，返回移动后游标是否有效
+func (c *Cursor[T]) Prev() bool {
+	if c.node != nil {
+		c.node = c.node.predecessor()
+	} else if c.tree.root != nil {
+		c.node = c.tree.root.maxSubNode()
+	}
+	return c.node != nil
+}
+
+// Seek 移动到第一个大于等于 val 的节点，返回移动后游标是否有效
+func (c *Cursor[T]) Seek(val T) bool {
+	c.node = c.tree.lowerBound(val)
+	return c.node != nil
+}
@@ -0,0 +1,82 @@
+package rbtree
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll(t *testing.T) {
+	rb := NewRBTree[int]()
+	require.Empty(t, slices.Collect(rb.All()))
+	require.Empty(t, slices.Collect(rb.Backward()))
+
+	data := GenUniqList()
+	for idx := range data {
+		rb.Insert(data[idx])
+	}
+	slices.Sort(data)
+	require.Equal(t, data, slices.Collect(rb.All()))
+
+	slices.Reverse(data)
+	require.Equal(t, data, slices.Collect(rb.Backward()))
+
+	// 提前结束遍历
+	cnt := 0
+	for range rb.All() {
+		cnt++
+		if cnt == 1 {
+			break
+		}
+	}
+	require.Equal(t, 1, cnt)
+}
+
+func TestCursor(t *testing.T) {
+	rb := NewRBTree[int]()
+	c := rb.Cursor()
+	require.False(t, c.Valid())
+	require.False(t, c.Next())
+	require.False(t, c.Prev())
+	require.False(t, c.Seek(1))
+
+	data := GenUniqList()
+	for idx := range data {
+		rb.Insert(data[idx])
+	}
+	slices.Sort(data)
+
+	// 正向遍历
+	res := make([]int, 0, len(data))
+	for c.Next() {
+		res = append(res, c.Value())
+	}
+	require.Equal(t, data, res)
+	require.False(t, c.Valid())
+	require.Zero(t, c.Value())
+
+	// 反向遍历
+	res = res[:0]
+	for c.Prev() {
+		res = append(res, c.Value())
+	}
+	slices.Reverse(res)
+	require.Equal(t, data, res)
+
+	// Seek 定位到第一个大于等于目标的值
+	for idx := range data {
+		require.True(t, c.Seek(data[idx]))
+		require.Equal(t, data[idx], c.Value())
+		if idx > 0 && data[idx-1]+1 < data[idx] {
+			require.True(t, c.Seek(data[idx-1]+1))
+			require.Equal(t, data[idx], c.Value())
+		}
+	}
+	require.False(t, c.Seek(data[len(data)-1]+1))
+
+	require.True(t, c.Seek(data[0]))
+	require.False(t, c.Prev())
+	require.True(t, c.Prev())
+	require.Equal(t, data[len(data)-1], c.Value())
+}
@@ -0,0 +1,85 @@
+package rbtree
+
+import "cmp"
+
+// mapEntry 有序字典的键值对，只按照 key 排序
+type mapEntry[K cmp.Ordered, V any] struct {
+	key K
+	val V
+}
+
+// compareEntry 比较两个键值对的 key
+func compareEntry[K cmp.Ordered, V any](a, b mapEntry[K, V]) int {
+	return cmp.Compare(a.key, b.key)
+}
+
+// RBMap 基于红黑树实现的有序字典
+//
+// 复用 RBTree 的插入、删除以及平衡逻辑，节点中存储的是键值对
+type RBMap[K cmp.Ordered, V any] struct {
+	tree *RBTree[mapEntry[K, V]]
+}
+
+// NewRBMap 创建有序字典
+func NewRBMap[K cmp.Ordered, V any]() *RBMap[K, V] {
+	return &RBMap[K, V]{
+		tree: NewRBTreeFunc(compareEntry[K, V]),
+	}
+}
+
+// Put 插入键值对，key 已存在时覆盖原先的值
+//
+// 返回是否插入了新的 key
+func (m *RBMap[K, V]) Put(key K, val V) bool {
+	node, inserted := m.tree.insert(mapEntry[K, V]{key: key, val: val})
+	if !inserted {
+		node.val.val = val
+	}
+	return inserted
+}
+
+// Get 查找 key 对应的值
+//
+// 返回值，以及 key 是否存在
+func (m *RBMap[K, V]) Get(key K) (V, bool) {
+	node := m.tree.find(mapEntry[K, V]{key: key})
+	if node == nil {
+		return *new(V), false
+	}
+	return node.val.val, true
+}
+
+// GetOrInsert 查找 key 对应的值，不存在时插入 val
+//
+// 返回 key 最终对应的值，以及 key 是否已经存在（true 表示已存在，未插入）
+func (m *RBMap[K, V]) GetOrInsert(key K, val V) (V, bool) {
+	node, inserted := m.tree.insert(mapEntry[K, V]{key: key, val: val})
+	return node.val.val, !inserted
+}
+
+// Update 使用 fn 更新 key 对应的值
+//
+// key 不存在时不做任何操作，返回 false
+func (m *RBMap[K, V]) Update(key K, fn func(old V) V) bool {
+	node := m.tree.find(mapEntry[K, V]{key: key})
+	if node == nil {
+		return false
+	}
+	node.val.val = fn(node.val.val)
+	return true
+}
+
+// Delete 删除 key，返回 key 是否存在
+func (m *RBMap[K, V]) Delete(key K) bool {
+	return m.tree.Delete(mapEntry[K, V]{key: key})
+}
+
+// Len 键值对数量
+func (m *RBMap[K, V]) Len() int {
+	return m.tree.Len()
+}
+
+// IsValid 验证底层红黑树的性质
+func (m *RBMap[K, V]) IsValid() bool {
+	return m.tree.IsValid()
+}
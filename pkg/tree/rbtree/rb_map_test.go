@@ -0,0 +1,77 @@
+package rbtree
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRBMapPutGet(t *testing.T) {
+	m := NewRBMap[int, string]()
+	_, ok := m.Get(1)
+	require.False(t, ok)
+
+	data := GenBFSList()
+	for _, v := range data {
+		m.Put(v, strconv.Itoa(v))
+	}
+	require.True(t, m.IsValid())
+	for _, v := range data {
+		val, ok := m.Get(v)
+		require.True(t, ok)
+		require.Equal(t, strconv.Itoa(v), val)
+	}
+
+	// 覆盖已有的值
+	require.False(t, m.Put(data[0], "overwrite"))
+	val, ok := m.Get(data[0])
+	require.True(t, ok)
+	require.Equal(t, "overwrite", val)
+}
+
+func TestRBMapGetOrInsert(t *testing.T) {
+	m := NewRBMap[string, int]()
+	val, loaded := m.GetOrInsert("a", 1)
+	require.False(t, loaded)
+	require.Equal(t, 1, val)
+
+	val, loaded = m.GetOrInsert("a", 2)
+	require.True(t, loaded)
+	require.Equal(t, 1, val)
+}
+
+func TestRBMapUpdate(t *testing.T) {
+	m := NewRBMap[string, int]()
+	require.False(t, m.Update("a", func(old int) int { return old + 1 }))
+
+	m.Put("a", 1)
+	require.True(t, m.Update("a", func(old int) int { return old + 1 }))
+	val, ok := m.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 2, val)
+}
+
+func TestRBMapDelete(t *testing.T) {
+	data := GenUniqList()
+	m := NewRBMap[int, int]()
+	for _, v := range data {
+		m.Put(v, v*2)
+	}
+
+	delCnt := len(data) / 2
+	for _, v := range data[:delCnt] {
+		require.True(t, m.Delete(v))
+		require.False(t, m.Delete(v))
+		_, ok := m.Get(v)
+		require.False(t, ok)
+	}
+	require.True(t, m.IsValid())
+
+	// 删除后剩余的键值对保持不变
+	for _, v := range data[delCnt:] {
+		val, ok := m.Get(v)
+		require.True(t, ok)
+		require.Equal(t, v*2, val)
+	}
+}
@@ -0,0 +1,42 @@
+package rbtree
+
+import "cmp"
+
+// NewRBMultiset 创建允许重复元素的红黑树（多重集合）
+func NewRBMultiset[T cmp.Ordered]() *RBTree[T] {
+	rb := NewRBTree[T]()
+	rb.multi = true
+	return rb
+}
+
+// NewRBMultisetFunc 使用自定义比较函数创建多重集合
+//
+// 比较结果相等的元素会按照插入顺序依次保存，例如截止时间相同的多个任务
+func NewRBMultisetFunc[T any](cmp func(a, b T) int) *RBTree[T] {
+	rb := NewRBTreeFunc(cmp)
+	rb.multi = true
+	return rb
+}
+
+// Count 与 val 相等的元素数量
+func (rb *RBTree[T]) Count(val T) int {
+	return rb.rank(val, true) - rb.rank(val, false)
+}
+
+// DeleteOne 删除一个与 val 相等的元素（多重集合模式下为最先插入的元素）
+//
+// 返回是否删除了元素
+func (rb *RBTree[T]) DeleteOne(val T) bool {
+	return rb.Delete(val)
+}
+
+// DeleteAll 删除所有与 val 相等的元素
+//
+// 返回删除的元素数量
+func (rb *RBTree[T]) DeleteAll(val T) int {
+	cnt := 0
+	for rb.Delete(val) {
+		cnt++
+	}
+	return cnt
+}
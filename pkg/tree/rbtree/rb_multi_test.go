@@ -0,0 +1,91 @@
+package rbtree
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiset(t *testing.T) {
+	rb := NewRBMultiset[int]()
+	data := GenBFSList()
+	cnt := make(map[int]int)
+	for idx := range data {
+		rb.Insert(data[idx])
+		rb.Insert(data[idx])
+		cnt[data[idx]] += 2
+	}
+	require.Equal(t, 2*len(data), rb.Len())
+	require.True(t, rb.IsValid())
+	for v, c := range cnt {
+		require.Equal(t, c, rb.Count(v))
+	}
+	require.Equal(t, 0, rb.Count(-1))
+
+	sorted := slices.Concat(data, data)
+	slices.Sort(sorted)
+	require.Equal(t, sorted, slices.Collect(rb.All()))
+
+	// 删除一个
+	for v := range cnt {
+		require.True(t, rb.DeleteOne(v))
+		cnt[v]--
+		require.Equal(t, cnt[v], rb.Count(v))
+	}
+	require.True(t, rb.IsValid())
+
+	// 删除全部
+	for v, c := range cnt {
+		n := rb.DeleteAll(v)
+		require.Equal(t, c, n)
+		require.Equal(t, 0, rb.Count(v))
+		require.Nil(t, rb.Find(v))
+	}
+	require.Equal(t, 0, rb.Len())
+	require.True(t, rb.IsValid())
+}
+
+func TestMultisetFunc(t *testing.T) {
+	type job struct {
+		deadline int
+		name     string
+	}
+	rb := NewRBMultisetFunc(func(a, b job) int {
+		return cmp.Compare(a.deadline, b.deadline)
+	})
+	jobs := []job{{3, "a"}, {1, "b"}, {3, "c"}, {2, "d"}, {3, "e"}, {1, "f"}}
+	for _, j := range jobs {
+		rb.Insert(j)
+	}
+	require.True(t, rb.IsValid())
+	require.Equal(t, 3, rb.Count(job{deadline: 3}))
+
+	// 截止时间相同的任务按照插入顺序排列
+	want := []job{{1, "b"}, {1, "f"}, {2, "d"}, {3, "a"}, {3, "c"}, {3, "e"}}
+	require.Equal(t, want, slices.Collect(rb.All()))
+	require.Equal(t, "a", rb.Find(job{deadline: 3}).Value().name)
+
+	// DeleteOne 删除最先插入的任务
+	rb.DeleteOne(job{deadline: 3})
+	require.Equal(t, "c", rb.Find(job{deadline: 3}).Value().name)
+	require.Equal(t, 2, rb.Count(job{deadline: 3}))
+
+	n := rb.DeleteAll(job{deadline: 1})
+	require.Equal(t, 2, n)
+	want = []job{{2, "d"}, {3, "c"}, {3, "e"}}
+	require.Equal(t, want, slices.Collect(rb.All()))
+	require.True(t, rb.IsValid())
+}
+
+func TestSetCount(t *testing.T) {
+	rb := NewRBTree[int]()
+	require.True(t, rb.Insert(1))
+	require.False(t, rb.Insert(1))
+	require.Equal(t, 1, rb.Count(1))
+	require.Equal(t, 1, rb.Len())
+	n := rb.DeleteAll(1)
+	require.Equal(t, 1, n)
+	require.Equal(t, 0, rb.Len())
+}
@@ -1,7 +1,5 @@
 package rbtree
 
-import "cmp"
-
 type rbColor bool
 
 const (
@@ -10,20 +8,50 @@ const (
 )
 
 // rbNode 红黑树节点
-type rbNode[T cmp.Ordered] struct {
-	val    T
-	color  rbColor
+type rbNode[T any] struct {
+	val   T
+	color rbColor
+	// gen 节点每次被删除时加 1，用于判断句柄是否失效；位于 color 之后的填充位置，不增加节点大小
+	gen    uint32
 	left   *rbNode[T]
 	right  *rbNode[T]
 	parent *rbNode[T]
+	size   int // 以当前节点为根的子树的节点数量
 }
 
 // newRBNode 创建新的红黑树节点
-func newRBNode[T cmp.Ordered](val T, color rbColor) *rbNode[T] {
+func newRBNode[T any](val T, color rbColor) *rbNode[T] {
 	return &rbNode[T]{
 		val:   val,
 		color: color,
+		size:  1,
+	}
+}
+
+// sizeOf 子树的节点数量，空节点为 0
+func sizeOf[T any](node *rbNode[T]) int {
+	if node == nil {
+		return 0
+	}
+	return node.size
+}
+
+// update 根据左右孩子重新计算子树的节点数量以及增强值，aug 为 nil 表示不需要维护增强值
+//
+// 调用前需要保证左右孩子已经是最新的
+func (rb *rbNode[T]) update(aug Augmenter[T]) {
+	rb.size = 1 + sizeOf(rb.left) + sizeOf(rb.right)
+	if aug == nil {
+		return
+	}
+	var left, right *T
+	if rb.left != nil {
+		left = &rb.left.val
 	}
+	if rb.right != nil {
+		right = &rb.right.val
+	}
+	aug.Recompute(&rb.val, left, right)
 }
 
 // isRedViolation 判断节点的前后是否存在连续的红色
@@ -110,6 +138,41 @@ func (rb *rbNode[T]) minSubNode() *rbNode[T] {
 	return cur
 }
 
+// maxSubNode 求最大节点
+func (rb *rbNode[T]) maxSubNode() *rbNode[T] {
+	cur := rb
+	for cur.right != nil {
+		cur = cur.right
+	}
+	return cur
+}
+
+// successor 中序遍历的后继节点，不存在返回 nil
+//
+// 借助 parent 指针回溯，不需要额外的栈
+func (rb *rbNode[T]) successor() *rbNode[T] {
+	if rb.right != nil {
+		return rb.right.minSubNode()
+	}
+	cur, parent := rb, rb.parent
+	for parent != nil && parent.right == cur {
+		cur, parent = parent, parent.parent
+	}
+	return parent
+}
+
+// predecessor 中序遍历的前驱节点，不存在返回 nil
+func (rb *rbNode[T]) predecessor() *rbNode[T] {
+	if rb.left != nil {
+		return rb.left.maxSubNode()
+	}
+	cur, parent := rb, rb.parent
+	for parent != nil && parent.left == cur {
+		cur, parent = parent, parent.parent
+	}
+	return parent
+}
+
 // getGrandparent 获取祖父节点
 func (rb *rbNode[T]) getGrandparent() *rbNode[T] {
 	if rb.parent != nil {
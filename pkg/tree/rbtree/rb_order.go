@@ -0,0 +1,57 @@
+package rbtree
+
+// Select 查找第 k 小的元素（k 从 0 开始）
+//
+// 借助子树大小在 O(log n) 内完成，k 越界时返回 false
+func (rb *RBTree[T]) Select(k int) (T, bool) {
+	if k < 0 || k >= rb.Len() {
+		return *new(T), false
+	}
+	cur := rb.root
+	for cur != nil {
+		leftSize := sizeOf(cur.left)
+		switch {
+		case k < leftSize:
+			cur = cur.left
+		case k == leftSize:
+			return cur.val, true
+		default:
+			k -= leftSize + 1
+			cur = cur.right
+		}
+	}
+	return *new(T), false
+}
+
+// Rank 小于 val 的元素数量
+//
+// val 存在时即为 val 从 0 开始的排名
+func (rb *RBTree[T]) Rank(val T) int {
+	return rb.rank(val, false)
+}
+
+// rank 小于 val 的元素数量，inclusive 为 true 时统计小于等于 val 的元素数量
+func (rb *RBTree[T]) rank(val T, inclusive bool) int {
+	var (
+		cur  = rb.root
+		rank = 0
+	)
+	for cur != nil {
+		c := rb.cmp(val, cur.val)
+		if c < 0 || (c == 0 && !inclusive) {
+			cur = cur.left
+		} else {
+			rank += sizeOf(cur.left) + 1
+			cur = cur.right
+		}
+	}
+	return rank
+}
+
+// CountRange 位于区间 [lo, hi) 的元素数量
+func (rb *RBTree[T]) CountRange(lo, hi T) int {
+	if rb.cmp(lo, hi) >= 0 {
+		return 0
+	}
+	return rb.Rank(hi) - rb.Rank(lo)
+}
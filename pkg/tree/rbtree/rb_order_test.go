@@ -0,0 +1,65 @@
+package rbtree
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectAndRank(t *testing.T) {
+	rb := NewRBTree[int]()
+	_, ok := rb.Select(0)
+	require.False(t, ok)
+	require.Equal(t, 0, rb.Rank(1))
+
+	data := GenUniqList()
+	for idx := range data {
+		rb.Insert(data[idx])
+	}
+	require.Equal(t, len(data), rb.Len())
+	require.True(t, rb.IsValid())
+
+	slices.Sort(data)
+	for k := range data {
+		val, ok := rb.Select(k)
+		require.True(t, ok)
+		require.Equal(t, data[k], val)
+		require.Equal(t, k, rb.Rank(data[k]))
+	}
+	_, ok = rb.Select(-1)
+	require.False(t, ok)
+	_, ok = rb.Select(len(data))
+	require.False(t, ok)
+	require.Equal(t, len(data), rb.Rank(data[len(data)-1]+1))
+}
+
+func TestCountRange(t *testing.T) {
+	rd := rand.New(rand.NewSource(int64(time.Now().UnixNano())))
+	data := GenUniqList()
+	rb := NewRBTree[int]()
+	for idx := range data {
+		rb.Insert(data[idx])
+	}
+
+	// 删除部分元素后子树大小仍然正确
+	for _, v := range data[:len(data)/3] {
+		rb.Delete(v)
+	}
+	data = data[len(data)/3:]
+	require.Equal(t, len(data), rb.Len())
+	require.True(t, rb.IsValid())
+
+	slices.Sort(data)
+	for range 100 {
+		lo, hi := rd.Intn(1100)-50, rd.Intn(1100)-50
+		want := 0
+		if lo < hi {
+			want = sort.SearchInts(data, hi) - sort.SearchInts(data, lo)
+		}
+		require.Equal(t, want, rb.CountRange(lo, hi))
+	}
+}
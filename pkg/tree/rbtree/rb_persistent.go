@@ -0,0 +1,509 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+)
+
+// pNode 持久化红黑树节点
+//
+// 节点创建后不再修改，没有 parent 指针，才能在多个版本之间共享子树；
+// size 为子树的节点数量，用于 Rank、Select
+type pNode[T any] struct {
+	val   T
+	color rbColor
+	left  *pNode[T]
+	right *pNode[T]
+	size  int
+}
+
+// newPNode 创建持久化红黑树节点
+func newPNode[T any](val T, color rbColor, left, right *pNode[T]) *pNode[T] {
+	n := &pNode[T]{
+		val:   val,
+		color: color,
+		left:  left,
+		right: right,
+	}
+	return n.pull()
+}
+
+// clone 复制节点（路径复制）
+func (n *pNode[T]) clone() *pNode[T] {
+	cp := *n
+	return &cp
+}
+
+// pull 修改复制后节点的孩子之后，重新计算子树的节点数量
+func (n *pNode[T]) pull() *pNode[T] {
+	n.size = sizeOfP(n.left) + sizeOfP(n.right) + 1
+	return n
+}
+
+// sizeOfP 子树的节点数量，空节点为 0
+func sizeOfP[T any](n *pNode[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// isRedP 是否是红色，空节点视为黑色
+func isRedP[T any](n *pNode[T]) bool {
+	return n != nil && n.color == red
+}
+
+// PersistentRBTree 持久化（不可变）红黑树
+//
+// Insert、Delete 不修改当前版本，而是通过路径复制返回新的版本，
+// 未修改的子树在新旧版本之间共享，旧版本可以继续安全地读取
+type PersistentRBTree[T any] struct {
+	root *pNode[T]
+	cmp  func(a, b T) int
+	size int
+}
+
+// NewPersistentRBTree 创建持久化红黑树
+func NewPersistentRBTree[T cmp.Ordered]() *PersistentRBTree[T] {
+	return NewPersistentRBTreeFunc(cmp.Compare[T])
+}
+
+// NewPersistentRBTreeFunc 使用自定义比较函数创建持久化红黑树
+func NewPersistentRBTreeFunc[T any](cmp func(a, b T) int) *PersistentRBTree[T] {
+	return &PersistentRBTree[T]{cmp: cmp}
+}
+
+// Len 元素数量
+func (t *PersistentRBTree[T]) Len() int {
+	return t.size
+}
+
+// Find 查找元素
+func (t *PersistentRBTree[T]) Find(val T) (T, bool) {
+	cur := t.root
+	for cur != nil {
+		switch c := t.cmp(val, cur.val); {
+		case c < 0:
+			cur = cur.left
+		case c == 0:
+			return cur.val, true
+		default:
+			cur = cur.right
+		}
+	}
+	return *new(T), false
+}
+
+// All 按照从小到大的顺序遍历
+func (t *PersistentRBTree[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var (
+			stack = make([]*pNode[T], 0)
+			cur   = t.root
+		)
+		for len(stack) > 0 || cur != nil {
+			if cur != nil {
+				stack = append(stack, cur)
+				cur = cur.left
+				continue
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(top.val) {
+				return
+			}
+			cur = top.right
+		}
+	}
+}
+
+// Backward 按照从大到小的顺序遍历
+func (t *PersistentRBTree[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		var (
+			stack = make([]*pNode[T], 0)
+			cur   = t.root
+		)
+		for len(stack) > 0 || cur != nil {
+			if cur != nil {
+				stack = append(stack, cur)
+				cur = cur.right
+				continue
+			}
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if !yield(top.val) {
+				return
+			}
+			cur = top.left
+		}
+	}
+}
+
+// Range 按照从小到大的顺序遍历区间 lo ~ hi 内的元素
+//
+// 没有 parent 指针，先把查找 lo 的路径上位于区间左端点右侧的节点压栈，再继续中序遍历
+func (t *PersistentRBTree[T]) Range(lo, hi T, loInclusive, hiInclusive bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		stack := make([]*pNode[T], 0)
+		for cur := t.root; cur != nil; {
+			if c := t.cmp(cur.val, lo); c > 0 || (c == 0 && loInclusive) {
+				stack = append(stack, cur)
+				cur = cur.left
+			} else {
+				cur = cur.right
+			}
+		}
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if c := t.cmp(top.val, hi); c > 0 || (c == 0 && !hiInclusive) {
+				return
+			}
+			if !yield(top.val) {
+				return
+			}
+			for cur := top.right; cur != nil; cur = cur.left {
+				stack = append(stack, cur)
+			}
+		}
+	}
+}
+
+// Floor 小于等于 val 的最大元素
+func (t *PersistentRBTree[T]) Floor(val T) (T, bool) {
+	var res *pNode[T]
+	for cur := t.root; cur != nil; {
+		if t.cmp(cur.val, val) <= 0 {
+			res = cur
+			cur = cur.right
+		} else {
+			cur = cur.left
+		}
+	}
+	if res == nil {
+		return *new(T), false
+	}
+	return res.val, true
+}
+
+// Ceiling 大于等于 val 的最小元素
+func (t *PersistentRBTree[T]) Ceiling(val T) (T, bool) {
+	var res *pNode[T]
+	for cur := t.root; cur != nil; {
+		if t.cmp(cur.val, val) >= 0 {
+			res = cur
+			cur = cur.left
+		} else {
+			cur = cur.right
+		}
+	}
+	if res == nil {
+		return *new(T), false
+	}
+	return res.val, true
+}
+
+// Rank 小于 val 的元素数量
+func (t *PersistentRBTree[T]) Rank(val T) int {
+	rank := 0
+	for cur := t.root; cur != nil; {
+		if t.cmp(val, cur.val) <= 0 {
+			cur = cur.left
+		} else {
+			rank += sizeOfP(cur.left) + 1
+			cur = cur.right
+		}
+	}
+	return rank
+}
+
+// Select 查找第 k 小的元素（k 从 0 开始），k 越界时返回 false
+func (t *PersistentRBTree[T]) Select(k int) (T, bool) {
+	if k < 0 || k >= t.size {
+		return *new(T), false
+	}
+	for cur := t.root; cur != nil; {
+		leftSize := sizeOfP(cur.left)
+		switch {
+		case k < leftSize:
+			cur = cur.left
+		case k == leftSize:
+			return cur.val, true
+		default:
+			k -= leftSize + 1
+			cur = cur.right
+		}
+	}
+	return *new(T), false
+}
+
+// Insert 插入元素，返回新的版本；元素已存在时返回当前版本
+func (t *PersistentRBTree[T]) Insert(val T) *PersistentRBTree[T] {
+	root, inserted := t.insert(t.root, val)
+	if !inserted {
+		return t
+	}
+	if root.color == red {
+		root = root.clone()
+		root.color = black
+	}
+	return &PersistentRBTree[T]{root: root, cmp: t.cmp, size: t.size + 1}
+}
+
+// Delete 删除元素，返回新的版本；元素不存在时返回当前版本
+func (t *PersistentRBTree[T]) Delete(val T) *PersistentRBTree[T] {
+	if _, ok := t.Find(val); !ok {
+		return t
+	}
+	root, _ := t.delete(t.root, val)
+	if root != nil && root.color == red {
+		root = root.clone()
+		root.color = black
+	}
+	return &PersistentRBTree[T]{root: root, cmp: t.cmp, size: t.size - 1}
+}
+
+// IsValid 验证红黑树所有性质：
+//
+// 1. 根节点为黑色
+// 2. 红色节点不能连续出现
+// 3. 所有路径黑高一致
+// 4. 满足二叉搜索树的有序性
+// 5. 子树的节点数量正确
+func (t *PersistentRBTree[T]) IsValid() bool {
+	if t.root == nil {
+		return t.size == 0
+	}
+	if t.root.color != black {
+		return false
+	}
+	_, cnt, ok := t.verify(t.root, nil, nil)
+	return ok && cnt == t.size
+}
+
+// verify 递归验证子树，lo、hi 为子树中元素的开区间边界（nil 表示无边界）
+//
+// 返回黑高、节点数量以及是否符合红黑树定义
+func (t *PersistentRBTree[T]) verify(n *pNode[T], lo, hi *T) (int, int, bool) {
+	if n == nil {
+		return 1, 0, true
+	}
+	if (lo != nil && t.cmp(*lo, n.val) >= 0) || (hi != nil && t.cmp(n.val, *hi) >= 0) {
+		return 0, 0, false
+	}
+	if n.color == red && (isRedP(n.left) || isRedP(n.right)) {
+		return 0, 0, false
+	}
+	bhLeft, cntLeft, ok := t.verify(n.left, lo, &n.val)
+	if !ok {
+		return 0, 0, false
+	}
+	bhRight, cntRight, ok := t.verify(n.right, &n.val, hi)
+	if !ok || bhLeft != bhRight || n.size != cntLeft+cntRight+1 {
+		return 0, 0, false
+	}
+	if n.color == black {
+		bhLeft++
+	}
+	return bhLeft, n.size, true
+}
+
+// insert 递归插入，沿途复制节点，返回新的子树根节点以及是否插入
+func (t *PersistentRBTree[T]) insert(n *pNode[T], val T) (*pNode[T], bool) {
+	if n == nil {
+		return newPNode(val, red, nil, nil), true
+	}
+	var (
+		child    *pNode[T]
+		inserted bool
+		cp       *pNode[T]
+	)
+	switch c := t.cmp(val, n.val); {
+	case c < 0:
+		if child, inserted = t.insert(n.left, val); !inserted {
+			return n, false
+		}
+		cp = n.clone()
+		cp.left = child
+	case c > 0:
+		if child, inserted = t.insert(n.right, val); !inserted {
+			return n, false
+		}
+		cp = n.clone()
+		cp.right = child
+	default:
+		return n, false
+	}
+	return balanceP(cp.pull()), true
+}
+
+// balanceP 修复插入后黑色节点下连续的红色节点
+//
+// 四种 LL、LR、RR、RL 型的情况都调整成：红色根节点 + 两个黑色孩子
+//
+//	   z(B)            z(B)          x(B)          x(B)
+//	   /               /               \             \             y(R)
+//	 y(R)            x(R)              z(R)          y(R)   ---->  /   \
+//	 /                 \               /               \         x(B)  z(B)
+//	x(R)               y(R)          y(R)              z(R)
+func balanceP[T any](n *pNode[T]) *pNode[T] {
+	if n.color != black {
+		return n
+	}
+	var x, y, z *pNode[T]
+	switch {
+	case isRedP(n.left) && isRedP(n.left.left):
+		x, y, z = n.left.left, n.left, n
+		x = newPNode(x.val, black, x.left, x.right)
+		z = newPNode(z.val, black, y.right, z.right)
+	case isRedP(n.left) && isRedP(n.left.right):
+		x, y, z = n.left, n.left.right, n
+		x = newPNode(x.val, black, x.left, y.left)
+		z = newPNode(z.val, black, y.right, z.right)
+	case isRedP(n.right) && isRedP(n.right.left):
+		x, y, z = n, n.right.left, n.right
+		x = newPNode(x.val, black, x.left, y.left)
+		z = newPNode(z.val, black, y.right, z.right)
+	case isRedP(n.right) && isRedP(n.right.right):
+		x, y, z = n, n.right, n.right.right
+		x = newPNode(x.val, black, x.left, y.left)
+		z = newPNode(z.val, black, z.left, z.right)
+	default:
+		return n
+	}
+	return newPNode(y.val, red, x, z)
+}
+
+// delete 递归删除，沿途复制节点
+//
+// 返回新的子树根节点，以及子树黑高是否减少了 1
+func (t *PersistentRBTree[T]) delete(n *pNode[T], val T) (*pNode[T], bool) {
+	switch c := t.cmp(val, n.val); {
+	case c < 0:
+		child, shorter := t.delete(n.left, val)
+		cp := n.clone()
+		cp.left = child
+		cp.pull()
+		if shorter {
+			return fixLeftP(cp)
+		}
+		return cp, false
+	case c > 0:
+		child, shorter := t.delete(n.right, val)
+		cp := n.clone()
+		cp.right = child
+		cp.pull()
+		if shorter {
+			return fixRightP(cp)
+		}
+		return cp, false
+	}
+
+	// 删除当前节点
+	if n.left == nil || n.right == nil {
+		child := n.left
+		if child == nil {
+			child = n.right
+		}
+		if n.color == red {
+			// 红色节点最多只有空孩子
+			return child, false
+		}
+		if child != nil {
+			// 黑色节点只有一个孩子时，孩子一定为红色，染黑即可
+			return newPNode(child.val, black, child.left, child.right), false
+		}
+		return nil, true
+	}
+
+	// 使用右子树的最小值替代，再删除右子树的最小值
+	minRight := n.right
+	for minRight.left != nil {
+		minRight = minRight.left
+	}
+	child, shorter := t.delete(n.right, minRight.val)
+	cp := newPNode(minRight.val, n.color, n.left, child)
+	if shorter {
+		return fixRightP(cp)
+	}
+	return cp, false
+}
+
+// fixLeftP 左子树黑高比右子树少 1 时修复，n 为已经复制过的节点
+//
+// 与 fixDeletion 的情况一一对应，返回新的子树根节点以及子树黑高是否仍然减少了 1
+func fixLeftP[T any](n *pNode[T]) (*pNode[T], bool) {
+	bro := n.right
+	if bro.color == red {
+		// 情况 2：兄弟节点为红色，旋转后转换成兄弟节点为黑色的情况
+		n.color = red
+		n.right = bro.left
+		n.pull()
+		fixed, _ := fixLeftP(n)
+		return newPNode(bro.val, black, fixed, bro.right), false
+	}
+	switch {
+	case isRedP(bro.right):
+		// 情况 3.4：远侄子为红色
+		far := newPNode(bro.right.val, black, bro.right.left, bro.right.right)
+		color := n.color
+		n.color = black
+		n.right = bro.left
+		n.pull()
+		return newPNode(bro.val, color, n, far), false
+	case isRedP(bro.left):
+		// 情况 3.3：近侄子为红色，远侄子为黑色
+		near := bro.left
+		color := n.color
+		n.color = black
+		n.right = near.left
+		n.pull()
+		s := newPNode(bro.val, black, near.right, bro.right)
+		return newPNode(near.val, color, n, s), false
+	default:
+		// 情况 3.1 / 3.2：侄子都为黑色
+		n.right = newPNode(bro.val, red, bro.left, bro.right)
+		if n.color == red {
+			n.color = black
+			return n, false
+		}
+		return n, true
+	}
+}
+
+// fixRightP 右子树黑高比左子树少 1 时修复，与 fixLeftP 对称
+func fixRightP[T any](n *pNode[T]) (*pNode[T], bool) {
+	bro := n.left
+	if bro.color == red {
+		n.color = red
+		n.left = bro.right
+		n.pull()
+		fixed, _ := fixRightP(n)
+		return newPNode(bro.val, black, bro.left, fixed), false
+	}
+	switch {
+	case isRedP(bro.left):
+		far := newPNode(bro.left.val, black, bro.left.left, bro.left.right)
+		color := n.color
+		n.color = black
+		n.left = bro.right
+		n.pull()
+		return newPNode(bro.val, color, far, n), false
+	case isRedP(bro.right):
+		near := bro.right
+		color := n.color
+		n.color = black
+		n.left = near.right
+		n.pull()
+		s := newPNode(bro.val, black, bro.left, near.left)
+		return newPNode(near.val, color, s, n), false
+	default:
+		n.left = newPNode(bro.val, red, bro.left, bro.right)
+		if n.color == red {
+			n.color = black
+			return n, false
+		}
+		return n, true
+	}
+}
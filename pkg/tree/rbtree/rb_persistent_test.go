@@ -0,0 +1,137 @@
+package rbtree
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentInsert(t *testing.T) {
+	var (
+		data     = GenUniqList()
+		versions = make([]*PersistentRBTree[int], 0, len(data)+1)
+		cur      = NewPersistentRBTree[int]()
+	)
+	versions = append(versions, cur)
+	for _, v := range data {
+		cur = cur.Insert(v)
+		require.True(t, cur.IsValid())
+		versions = append(versions, cur)
+	}
+
+	// 每个版本只包含插入到当时为止的元素
+	for i, ver := range versions {
+		want := slices.Clone(data[:i])
+		slices.Sort(want)
+		require.Equal(t, i, ver.Len())
+		require.Equal(t, want, append([]int{}, slices.Collect(ver.All())...))
+	}
+
+	// 重复插入返回原版本
+	require.Same(t, cur, cur.Insert(data[0]))
+}
+
+func TestPersistentDelete(t *testing.T) {
+	rd := rand.New(rand.NewSource(int64(time.Now().UnixNano())))
+	for range 100 {
+		data := GenUniqList()
+		base := NewPersistentRBTree[int]()
+		for _, v := range data {
+			base = base.Insert(v)
+		}
+		sorted := slices.Clone(data)
+		slices.Sort(sorted)
+
+		cur := base
+		remain := make(map[int]struct{}, len(data))
+		for _, v := range data {
+			remain[v] = struct{}{}
+		}
+		for _, i := range rd.Perm(len(data))[:len(data)/2] {
+			cur = cur.Delete(data[i])
+			delete(remain, data[i])
+			require.True(t, cur.IsValid())
+			_, ok := cur.Find(data[i])
+			require.False(t, ok)
+		}
+		require.Equal(t, len(remain), cur.Len())
+		for v := range remain {
+			_, ok := cur.Find(v)
+			require.True(t, ok)
+		}
+
+		// 旧版本不受影响
+		require.True(t, base.IsValid())
+		require.Equal(t, sorted, slices.Collect(base.All()))
+
+		// 删除不存在的元素返回原版本
+		require.Same(t, cur, cur.Delete(-1))
+	}
+}
+
+func TestPersistentIsValid(t *testing.T) {
+	// 红色根节点
+	tree := &PersistentRBTree[int]{root: newPNode(1, red, nil, nil), cmp: cmp.Compare[int], size: 1}
+	require.False(t, tree.IsValid())
+
+	// 不满足有序性
+	tree.root = newPNode(2, black, newPNode(3, red, nil, nil), nil)
+	tree.size = 2
+	require.False(t, tree.IsValid())
+
+	// 黑高不一致
+	tree.root = newPNode(2, black, newPNode(1, black, nil, nil), nil)
+	require.False(t, tree.IsValid())
+
+	tree.root = newPNode(2, black, newPNode(1, red, nil, nil), nil)
+	require.True(t, tree.IsValid())
+}
+
+func TestPersistentOrder(t *testing.T) {
+	data := GenUniqList()
+	var (
+		pt = NewPersistentRBTree[int]()
+		rb = NewRBTree[int]()
+	)
+	for _, v := range data {
+		pt = pt.Insert(v * 2)
+		rb.Insert(v * 2)
+	}
+	for _, v := range data[:len(data)/3] {
+		pt = pt.Delete(v * 2)
+		rb.Delete(v * 2)
+	}
+	require.True(t, pt.IsValid())
+
+	// 与普通红黑树的结果一致
+	require.Equal(t, slices.Collect(rb.Backward()), slices.Collect(pt.Backward()))
+	for k := -1; k <= rb.Len(); k++ {
+		want, wantOk := rb.Select(k)
+		got, ok := pt.Select(k)
+		require.Equal(t, wantOk, ok)
+		require.Equal(t, want, got)
+	}
+	for _, v := range data {
+		for _, q := range []int{v*2 - 1, v * 2, v*2 + 1} {
+			require.Equal(t, rb.Rank(q), pt.Rank(q))
+			want, wantOk := rb.Floor(q)
+			got, ok := pt.Floor(q)
+			require.Equal(t, wantOk, ok)
+			require.Equal(t, want, got)
+			want, wantOk = rb.Ceiling(q)
+			got, ok = pt.Ceiling(q)
+			require.Equal(t, wantOk, ok)
+			require.Equal(t, want, got)
+		}
+	}
+	for i := range data {
+		lo, hi := min(data[i], data[len(data)-1-i])*2, max(data[i], data[len(data)-1-i])*2
+		for _, inc := range [][2]bool{{true, true}, {true, false}, {false, true}, {false, false}} {
+			require.Equal(t, slices.Collect(rb.Range(lo, hi, inc[0], inc[1])), slices.Collect(pt.Range(lo, hi, inc[0], inc[1])))
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package rbtree
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// mark 节点颜色的标记
+func (c rbColor) mark() string {
+	if c == red {
+		return "R"
+	}
+	return "B"
+}
+
+// WriteDOT 以 Graphviz DOT 格式输出红黑树，节点按照颜色填充，NIL 节点绘制成黑点
+//
+// 可以通过 dot -Tpng 生成图片
+func (rb *RBTree[T]) WriteDOT(w io.Writer) error {
+	var (
+		sb strings.Builder
+		id = 0
+	)
+	sb.WriteString("digraph RBTree {\n")
+	sb.WriteString("\tnode [shape=circle, style=filled, fontcolor=white];\n")
+
+	// writeNode 先序输出节点以及与孩子的连线，返回节点编号
+	var writeNode func(node *rbNode[T]) int
+	writeNode = func(node *rbNode[T]) int {
+		cur := id
+		id++
+		if node == nil {
+			fmt.Fprintf(&sb, "\tn%d [shape=point, fillcolor=black];\n", cur)
+			return cur
+		}
+		fillColor := "black"
+		if node.color == red {
+			fillColor = "red"
+		}
+		fmt.Fprintf(&sb, "\tn%d [label=%s, fillcolor=%s];\n", cur, dotQuote(fmt.Sprint(node.val)), fillColor)
+		left := writeNode(node.left)
+		right := writeNode(node.right)
+		fmt.Fprintf(&sb, "\tn%d -> n%d;\n", cur, left)
+		fmt.Fprintf(&sb, "\tn%d -> n%d;\n", cur, right)
+		return cur
+	}
+	if rb.root != nil {
+		writeNode(rb.root)
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// dotQuote 转换为 DOT 的带引号字符串
+//
+// DOT 的字符串只需要转义双引号以及反斜杠，其余字符（包括中文等 UTF-8 字符）原样输出
+func dotQuote(s string) string {
+	var sb strings.Builder
+	sb.Grow(len(s) + 2)
+	sb.WriteByte('"')
+	for _, c := range s {
+		if c == '"' || c == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(c)
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// String 横向输出红黑树：右子树在上，左子树在下，每个节点标记颜色 R / B
+//
+//	        /-- 9(B)
+//	    /-- 7(R)
+//	    |   \-- 6(B)
+//	5(B)
+//	    \-- 3(B)
+func (rb *RBTree[T]) String() string {
+	if rb.root == nil {
+		return "(empty)\n"
+	}
+	var sb strings.Builder
+	writeSideways(&sb, rb.root, "", posRoot)
+	return sb.String()
+}
+
+// 节点相对于父节点的位置
+const (
+	posRoot = iota
+	posLeft
+	posRight
+)
+
+// writeSideways 递归输出子树，先输出右子树，再输出当前节点，最后输出左子树
+//
+// prefix 为子树各行的公共前缀，pos 为当前节点相对于父节点的位置
+func writeSideways[T any](sb *strings.Builder, node *rbNode[T], prefix string, pos int) {
+	if node.right != nil {
+		// 当前节点是左孩子时，右子树与父节点之间需要竖线连接
+		next := prefix + "    "
+		if pos == posLeft {
+			next = prefix + "|   "
+		}
+		writeSideways(sb, node.right, next, posRight)
+	}
+
+	sb.WriteString(prefix)
+	switch pos {
+	case posLeft:
+		sb.WriteString("\\-- ")
+	case posRight:
+		sb.WriteString("/-- ")
+	}
+	fmt.Fprintf(sb, "%v(%s)\n", node.val, node.color.mark())
+
+	if node.left != nil {
+		next := prefix + "    "
+		if pos == posRight {
+			next = prefix + "|   "
+		}
+		writeSideways(sb, node.left, next, posLeft)
+	}
+}
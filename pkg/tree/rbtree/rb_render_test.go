@@ -0,0 +1,98 @@
+package rbtree
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	rb := NewRBTree[int]()
+	require.Equal(t, "(empty)\n", rb.String())
+
+	//      5(B)
+	//     /    \
+	//  3(B)    7(R)
+	//          /  \
+	//       6(B)  9(B)
+	var (
+		n5 = newRBNode(5, black)
+		n3 = newRBNode(3, black)
+		n7 = newRBNode(7, red)
+		n6 = newRBNode(6, black)
+		n9 = newRBNode(9, black)
+	)
+	n5.left, n5.right = n3, n7
+	n7.left, n7.right = n6, n9
+	rb.root = n5
+
+	want := strings.Join([]string{
+		"        /-- 9(B)",
+		"    /-- 7(R)",
+		"    |   \\-- 6(B)",
+		"5(B)",
+		"    \\-- 3(B)",
+		"",
+	}, "\n")
+	require.Equal(t, want, rb.String())
+	require.Equal(t, want, fmt.Sprint(rb))
+}
+
+func TestStringLeftChild(t *testing.T) {
+	//    4(B)
+	//    /
+	//  2(R)
+	//     \
+	//     3(B)
+	var (
+		n4 = newRBNode(4, black)
+		n2 = newRBNode(2, red)
+		n3 = newRBNode(3, black)
+	)
+	n4.left, n2.right = n2, n3
+	rb := &RBTree[int]{root: n4}
+	want := strings.Join([]string{
+		"4(B)",
+		"    |   /-- 3(B)",
+		"    \\-- 2(R)",
+		"",
+	}, "\n")
+	require.Equal(t, want, rb.String())
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	rb := NewRBTree[string]()
+	require.NoError(t, rb.WriteDOT(&buf))
+	require.Equal(t, "digraph RBTree {\n\tnode [shape=circle, style=filled, fontcolor=white];\n}\n", buf.String())
+
+	for _, v := range []string{"b", "a", "c", "d"} {
+		rb.Insert(v)
+	}
+	buf.Reset()
+	require.NoError(t, rb.WriteDOT(&buf))
+	dot := buf.String()
+	require.True(t, strings.HasPrefix(dot, "digraph RBTree {"))
+	require.True(t, strings.HasSuffix(dot, "}\n"))
+	require.Contains(t, dot, `[label="b", fillcolor=black]`)
+	require.Contains(t, dot, `[label="d", fillcolor=red]`)
+	// 4 个节点，5 个 NIL 节点，每个节点两条边
+	require.Equal(t, 5, strings.Count(dot, "shape=point"))
+	require.Equal(t, 8, strings.Count(dot, "->"))
+
+	// 只转义双引号以及反斜杠，UTF-8 字符以及制表符原样输出
+	rb = NewRBTree[string]()
+	for _, v := range []string{"红黑树", `say "hi"`, `a\b`, "é\t"} {
+		rb.Insert(v)
+	}
+	buf.Reset()
+	require.NoError(t, rb.WriteDOT(&buf))
+	dot = buf.String()
+	require.Contains(t, dot, `[label="红黑树", fillcolor=`)
+	require.Contains(t, dot, `[label="say \"hi\"", fillcolor=`)
+	require.Contains(t, dot, `[label="a\\b", fillcolor=`)
+	require.Contains(t, dot, "[label=\"é\t\", fillcolor=")
+}
@@ -0,0 +1,106 @@
+package rbtree
+
+// 集合运算：对两棵树的中序遍历做线性归并，再由有序结果线性构建新的红黑树，
+// 整体复杂度 O(n + m)。两棵树需要使用相同的比较函数，结果沿用 a 的配置。
+//
+// 多重集合模式下，相等的元素一一配对：并集取较大的重复次数，交集取较小的重复次数，
+// 差集为重复次数之差
+
+// firstNode 最小节点，空树返回 nil
+func (rb *RBTree[T]) firstNode() *rbNode[T] {
+	if rb.root == nil {
+		return nil
+	}
+	return rb.root.minSubNode()
+}
+
+// mergeFunc 归并两棵树，onlyA、onlyB、both 分别表示是否保留只在 a 中、只在 b 中、同时存在的元素
+func mergeFunc[T any](a, b *RBTree[T], onlyA, onlyB, both bool) *RBTree[T] {
+	var (
+		res    = make([]T, 0, a.Len()+b.Len())
+		na, nb = a.firstNode(), b.firstNode()
+	)
+	for na != nil && nb != nil {
+		switch c := a.cmp(na.val, nb.val); {
+		case c < 0:
+			if onlyA {
+				res = append(res, na.val)
+			}
+			na = na.successor()
+		case c > 0:
+			if onlyB {
+				res = append(res, nb.val)
+			}
+			nb = nb.successor()
+		default:
+			if both {
+				res = append(res, na.val)
+			}
+			na, nb = na.successor(), nb.successor()
+		}
+	}
+	for ; onlyA && na != nil; na = na.successor() {
+		res = append(res, na.val)
+	}
+	for ; onlyB && nb != nil; nb = nb.successor() {
+		res = append(res, nb.val)
+	}
+
+	tree := a.emptyLike(nil)
+	tree.root = tree.buildSorted(res)
+	return tree
+}
+
+// Union 并集
+func Union[T any](a, b *RBTree[T]) *RBTree[T] {
+	return mergeFunc(a, b, true, true, true)
+}
+
+// Intersection 交集
+func Intersection[T any](a, b *RBTree[T]) *RBTree[T] {
+	return mergeFunc(a, b, false, false, true)
+}
+
+// Difference 差集，即属于 a 但不属于 b 的元素
+func Difference[T any](a, b *RBTree[T]) *RBTree[T] {
+	return mergeFunc(a, b, true, false, false)
+}
+
+// SymmetricDifference 对称差集，即只属于其中一棵树的元素
+func SymmetricDifference[T any](a, b *RBTree[T]) *RBTree[T] {
+	return mergeFunc(a, b, true, true, false)
+}
+
+// IsSubsetOf 当前树的元素是否都属于 other
+func (rb *RBTree[T]) IsSubsetOf(other *RBTree[T]) bool {
+	if rb.Len() > other.Len() {
+		return false
+	}
+	na, nb := rb.firstNode(), other.firstNode()
+	for na != nil && nb != nil {
+		switch c := rb.cmp(na.val, nb.val); {
+		case c < 0:
+			// na 在 other 中不存在
+			return false
+		case c > 0:
+			nb = nb.successor()
+		default:
+			na, nb = na.successor(), nb.successor()
+		}
+	}
+	return na == nil
+}
+
+// Equal 两棵树的元素是否完全相同
+func (rb *RBTree[T]) Equal(other *RBTree[T]) bool {
+	if rb.Len() != other.Len() {
+		return false
+	}
+	na, nb := rb.firstNode(), other.firstNode()
+	for ; na != nil && nb != nil; na, nb = na.successor(), nb.successor() {
+		if rb.cmp(na.val, nb.val) != 0 {
+			return false
+		}
+	}
+	return true
+}
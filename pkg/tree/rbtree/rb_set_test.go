@@ -0,0 +1,102 @@
+package rbtree
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetOperations(t *testing.T) {
+	for range 100 {
+		var (
+			dataA, dataB = GenUniqList(), GenUniqList()
+			a, b         = NewRBTree[int](), NewRBTree[int]()
+			inA, inB     = make(map[int]bool), make(map[int]bool)
+			all          = make(map[int]struct{})
+		)
+		for _, v := range dataA {
+			a.Insert(v)
+			inA[v] = true
+			all[v] = struct{}{}
+		}
+		for _, v := range dataB {
+			b.Insert(v)
+			inB[v] = true
+			all[v] = struct{}{}
+		}
+
+		expect := func(keep func(x, y bool) bool) []int {
+			res := make([]int, 0)
+			for v := range all {
+				if keep(inA[v], inB[v]) {
+					res = append(res, v)
+				}
+			}
+			slices.Sort(res)
+			return res
+		}
+		check := func(tree *RBTree[int], want []int) {
+			require.True(t, tree.IsValid())
+			require.Equal(t, len(want), tree.Len())
+			require.Equal(t, want, append([]int{}, slices.Collect(tree.All())...))
+		}
+
+		check(Union(a, b), expect(func(x, y bool) bool { return x || y }))
+		check(Intersection(a, b), expect(func(x, y bool) bool { return x && y }))
+		check(Difference(a, b), expect(func(x, y bool) bool { return x && !y }))
+		check(SymmetricDifference(a, b), expect(func(x, y bool) bool { return x != y }))
+
+		// 原来的树不受影响
+		require.Equal(t, len(inA), a.Len())
+		require.Equal(t, len(inB), b.Len())
+
+		require.True(t, Intersection(a, b).IsSubsetOf(a))
+		require.True(t, Intersection(a, b).IsSubsetOf(b))
+		require.True(t, a.IsSubsetOf(Union(a, b)))
+		require.True(t, a.Equal(Union(a, Intersection(a, b))))
+		require.Equal(t, a.Equal(b), Union(a, b).Len() == Intersection(a, b).Len())
+	}
+}
+
+func TestSetPredicates(t *testing.T) {
+	a, b, empty := NewRBTree[int](), NewRBTree[int](), NewRBTree[int]()
+	for _, v := range []int{1, 3, 5} {
+		a.Insert(v)
+	}
+	for _, v := range []int{1, 2, 3, 4, 5} {
+		b.Insert(v)
+	}
+	require.True(t, a.IsSubsetOf(b))
+	require.False(t, b.IsSubsetOf(a))
+	require.True(t, empty.IsSubsetOf(a))
+	require.False(t, a.IsSubsetOf(empty))
+	require.True(t, a.IsSubsetOf(a))
+
+	require.True(t, a.Equal(a))
+	require.False(t, a.Equal(b))
+	require.True(t, empty.Equal(NewRBTree[int]()))
+	b.Delete(2)
+	b.Delete(4)
+	require.True(t, a.Equal(b))
+
+	b.Delete(5)
+	b.Insert(6)
+	require.False(t, a.Equal(b))
+	require.False(t, a.IsSubsetOf(b))
+}
+
+func TestMultisetOperations(t *testing.T) {
+	a, b := NewRBMultiset[int](), NewRBMultiset[int]()
+	for _, v := range []int{1, 1, 1, 2, 3, 3} {
+		a.Insert(v)
+	}
+	for _, v := range []int{1, 3, 3, 3, 4} {
+		b.Insert(v)
+	}
+	require.Equal(t, []int{1, 1, 1, 2, 3, 3, 3, 4}, slices.Collect(Union(a, b).All()))
+	require.Equal(t, []int{1, 3, 3}, slices.Collect(Intersection(a, b).All()))
+	require.Equal(t, []int{1, 1, 2}, slices.Collect(Difference(a, b).All()))
+	require.Equal(t, []int{1, 1, 2, 3, 4}, slices.Collect(SymmetricDifference(a, b).All()))
+	require.True(t, Union(a, b).IsValid())
+}
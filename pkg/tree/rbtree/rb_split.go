@@ -0,0 +1,188 @@
+package rbtree
+
+import "errors"
+
+// ErrJoinOverlap 表示 Join 的两棵树的元素范围有重叠
+var ErrJoinOverlap = errors.New("join trees overlap")
+
+// blackHeight 子树的黑高（不包含 NIL 节点），空树为 0，需要沿着左边界遍历，复杂度 O(log n)
+func blackHeight[T any](node *rbNode[T]) int {
+	h := 0
+	for cur := node; cur != nil; cur = cur.left {
+		if cur.color == black {
+			h++
+		}
+	}
+	return h
+}
+
+// detach 将子树从父节点上摘下，作为一棵独立的红黑树
+//
+// 根节点染成黑色，不影响红黑树的性质
+func detach[T any](node *rbNode[T]) *rbNode[T] {
+	if node != nil {
+		node.parent = nil
+		node.color = black
+	}
+	return node
+}
+
+// childHeight 黑色节点的黑高为 bh 时，孩子 child 摘下（染黑）之后的黑高
+//
+// 孩子原先的黑高为 bh-1，红色的孩子染黑后黑高加 1
+func childHeight[T any](child *rbNode[T], bh int) int {
+	if child != nil && child.color == red {
+		return bh
+	}
+	return bh - 1
+}
+
+// Split 将红黑树按照 key 拆分成两棵红黑树
+//
+// left 包含所有小于 key 的元素，right 包含所有大于等于 key 的元素，复杂度 O(log n)。
+// 拆分会复用原有节点，拆分后原红黑树为空
+func (rb *RBTree[T]) Split(key T) (left, right *RBTree[T]) {
+	l, _, r, _ := rb.split(rb.root, blackHeight(rb.root), key)
+	rb.root = nil
+	left, right = rb.emptyLike(l), rb.emptyLike(r)
+	left.validate(nil)
+	right.validate(nil)
+	return left, right
+}
+
+// Join 连接两棵红黑树，要求 a 中所有元素都小于 b 中的元素
+//
+// 复杂度 O(log n)，a、b 使用相同的比较函数；连接会复用原有节点，连接后 a、b 都为空
+func Join[T any](a, b *RBTree[T]) (*RBTree[T], error) {
+	if a.root == nil || b.root == nil {
+		root := a.root
+		if root == nil {
+			root = b.root
+		}
+		a.root, b.root = nil, nil
+		return a.emptyLike(root), nil
+	}
+
+	if c := a.cmp(a.root.maxSubNode().val, b.root.minSubNode().val); c > 0 || (c == 0 && !a.multi) {
+		return nil, ErrJoinOverlap
+	}
+
+	// 摘下 b 中最小的节点直接作为连接点，不需要回收再重新分配
+	mid := b.root.minSubNode()
+	b.unlink(mid)
+	root, _ := a.join(detach(a.root), blackHeight(a.root), mid, detach(b.root), blackHeight(b.root))
+	a.root, b.root = nil, nil
+	res := a.emptyLike(root)
+	res.validate(nil)
+	return res, nil
+}
+
+// emptyLike 创建与当前红黑树配置相同的红黑树
+//
+// 开启 arena 时新树使用独立的 arena，不与当前红黑树共享空闲链表
+func (rb *RBTree[T]) emptyLike(root *rbNode[T]) *RBTree[T] {
+	return &RBTree[T]{
+		root:      root,
+		cmp:       rb.cmp,
+		multi:     rb.multi,
+		augmenter: rb.augmenter,
+		codec:     rb.codec,
+		arena:     rb.arena.derive(),
+		debug:     rb.debug,
+	}
+}
+
+// split 递归拆分子树，返回小于 key 以及大于等于 key 的两棵子树以及各自的黑高
+//
+// node 为黑色的子树根节点，bh 为其黑高；黑高沿着递归向下传递，连接时不需要再遍历边界计算，
+// 每次 join 的代价与两棵树的黑高之差成正比，求和后总的复杂度为 O(log n)
+func (rb *RBTree[T]) split(node *rbNode[T], bh int, key T) (l *rbNode[T], lbh int, r *rbNode[T], rbh int) {
+	if node == nil {
+		return nil, 0, nil, 0
+	}
+	lh, rh := childHeight(node.left, bh), childHeight(node.right, bh)
+	left, right := detach(node.left), detach(node.right)
+	if rb.cmp(node.val, key) < 0 {
+		// node 以及左子树都属于 l，继续拆分右子树
+		rl, rlh, rr, rrh := rb.split(right, rh, key)
+		l, lbh = rb.join(left, lh, node, rl, rlh)
+		return l, lbh, rr, rrh
+	}
+	ll, llh, lr, lrh := rb.split(left, lh, key)
+	r, rbh = rb.join(lr, lrh, node, right, rh)
+	return ll, llh, r, rbh
+}
+
+// join 以 mid 为连接点连接两棵红黑树 l、r（根节点为黑色，黑高分别为 bhL、bhR），返回新的根节点以及黑高
+//
+// 要求 l < mid < r。沿着黑高较大的树的边界向下，找到黑高与另一棵树相同的黑色节点 c，
+// 用红色的 mid 替代 c 的位置，c 与另一棵树分别作为 mid 的左右孩子，
+// 最后按照插入的情况修复连续的红色节点。
+// 只有 mid 到根节点路径上的节点发生变化，路径长度与黑高之差成正比
+func (rb *RBTree[T]) join(l *rbNode[T], bhL int, mid, r *rbNode[T], bhR int) (*rbNode[T], int) {
+	mid.left, mid.right, mid.parent = nil, nil, nil
+	if bhL == bhR {
+		mid.color = black
+		mid.left, mid.right = l, r
+		if l != nil {
+			l.parent = mid
+		}
+		if r != nil {
+			r.parent = mid
+		}
+		mid.update(rb.augmenter)
+		return mid, bhL + 1
+	}
+
+	var (
+		cur, parent *rbNode[T]
+		h           int
+		// tree 只用于复用插入的修复逻辑
+		tree = RBTree[T]{augmenter: rb.augmenter}
+	)
+	mid.color = red
+	if bhL > bhR {
+		// 沿着 l 的右边界向下查找
+		cur, h, tree.root = l, bhL, l
+		for cur != nil && !(cur.color == black && h == bhR) {
+			if cur.color == black {
+				h--
+			}
+			parent, cur = cur, cur.right
+		}
+		mid.left, mid.right = cur, r
+		parent.right = mid
+	} else {
+		// 沿着 r 的左边界向下查找
+		cur, h, tree.root = r, bhR, r
+		for cur != nil && !(cur.color == black && h == bhL) {
+			if cur.color == black {
+				h--
+			}
+			parent, cur = cur, cur.left
+		}
+		mid.left, mid.right = l, cur
+		parent.left = mid
+	}
+	mid.parent = parent
+	if mid.left != nil {
+		mid.left.parent = mid
+	}
+	if mid.right != nil {
+		mid.right.parent = mid
+	}
+	// 先自底向上更新 mid 到根节点路径上的子树大小以及增强值，旋转时会自行维护
+	for n := mid; n != nil; n = n.parent {
+		n.update(rb.augmenter)
+	}
+	// 拆分的中间过程无法校验整棵树，这里只暴露修复过程中的内部错误
+	grown, err := tree.fixInsertionGrow(mid)
+	if err != nil && rb.debug {
+		panic(err)
+	}
+	bh := max(bhL, bhR)
+	if grown {
+		bh++
+	}
+	return tree.root, bh
+}
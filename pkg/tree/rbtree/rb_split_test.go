@@ -0,0 +1,132 @@
+package rbtree
+
+import (
+	"math/bits"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplit(t *testing.T) {
+	rd := rand.New(rand.NewSource(int64(time.Now().UnixNano())))
+	for range 200 {
+		data := GenUniqList()
+		rb := newSumTree()
+		for idx := range data {
+			rb.Insert(sumItem{val: data[idx]})
+		}
+		slices.Sort(data)
+
+		key := rd.Intn(1100) - 50
+		left, right := rb.Split(sumItem{val: key})
+		require.Equal(t, 0, rb.Len())
+
+		i := sort.SearchInts(data, key)
+		require.True(t, left.IsValid())
+		require.True(t, right.IsValid())
+		require.Equal(t, append([]int{}, data[:i]...), sumValues(left))
+		require.Equal(t, append([]int{}, data[i:]...), sumValues(right))
+		checkSubtreeSum(t, left.Root())
+		checkSubtreeSum(t, right.Root())
+
+		// 拆分后的树仍然可以正常插入删除
+		left.Insert(sumItem{val: key - 1000})
+		right.Insert(sumItem{val: key + 1000})
+		require.True(t, left.IsValid())
+		require.True(t, right.IsValid())
+	}
+}
+
+func TestJoin(t *testing.T) {
+	rd := rand.New(rand.NewSource(int64(time.Now().UnixNano())))
+	for range 200 {
+		data := GenUniqList()
+		slices.Sort(data)
+
+		// 随机选择分界点，两棵树的大小差异可能很大
+		i := rd.Intn(len(data) + 1)
+		a, b := newSumTree(), newSumTree()
+		for _, v := range data[:i] {
+			a.Insert(sumItem{val: v})
+		}
+		for _, v := range data[i:] {
+			b.Insert(sumItem{val: v})
+		}
+
+		res, err := Join(a, b)
+		require.NoError(t, err)
+		require.Equal(t, 0, a.Len())
+		require.Equal(t, 0, b.Len())
+		require.True(t, res.IsValid())
+		require.Equal(t, data, sumValues(res))
+		checkSubtreeSum(t, res.Root())
+
+		// 拆分后再连接，结果不变
+		key := rd.Intn(1000)
+		left, right := res.Split(sumItem{val: key})
+		res, err = Join(left, right)
+		require.NoError(t, err)
+		require.True(t, res.IsValid())
+		require.Equal(t, data, sumValues(res))
+		checkSubtreeSum(t, res.Root())
+	}
+}
+
+func TestJoinOverlap(t *testing.T) {
+	a, b := NewRBTree[int](), NewRBTree[int]()
+	a.Insert(1)
+	a.Insert(5)
+	b.Insert(5)
+	_, err := Join(a, b)
+	require.ErrorIs(t, err, ErrJoinOverlap)
+
+	// 多重集合允许边界相等
+	ma, mb := NewRBMultiset[int](), NewRBMultiset[int]()
+	ma.Insert(5)
+	mb.Insert(5)
+	mb.Insert(5)
+	res, err := Join(ma, mb)
+	require.NoError(t, err)
+	require.Equal(t, 3, res.Count(5))
+	require.True(t, res.IsValid())
+
+	res, err = Join(NewRBTree[int](), NewRBTree[int]())
+	require.NoError(t, err)
+	require.Equal(t, 0, res.Len())
+}
+
+func TestSplitBlackHeight(t *testing.T) {
+	const n = 1 << 14
+	var (
+		rd      = rand.New(rand.NewSource(int64(time.Now().UnixNano())))
+		updates = 0
+		// countAugmenter 统计节点更新的次数
+		countAugmenter = AugmentFunc[int](func(*int, *int, *int) { updates++ })
+	)
+	for range 50 {
+		rb := NewRBTreeAugmented(func(a, b int) int { return a - b }, countAugmenter)
+		for _, v := range rd.Perm(n) {
+			rb.Insert(v)
+		}
+
+		// 沿着递归传递的黑高与实际的黑高一致
+		key := rd.Intn(n + 1)
+		updates = 0
+		l, lbh, r, rbh := rb.split(rb.root, blackHeight(rb.root), key)
+		require.Equal(t, blackHeight(l), lbh)
+		require.Equal(t, blackHeight(r), rbh)
+
+		// 只更新连接路径上的节点，总的更新次数为 O(log n)
+		require.Less(t, updates, 4*bits.Len(n), "key: %d", key)
+
+		left, right := rb.emptyLike(l), rb.emptyLike(r)
+		require.True(t, left.IsValid())
+		require.True(t, right.IsValid())
+		require.Equal(t, key, left.Len())
+		require.Equal(t, n-key, right.Len())
+	}
+}
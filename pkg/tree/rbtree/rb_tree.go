@@ -6,100 +6,179 @@ import (
 )
 
 // RBTree 红黑树
-type RBTree[T cmp.Ordered] struct {
+type RBTree[T any] struct {
 	root *rbNode[T]
+	cmp  func(a, b T) int // 比较函数，a < b 返回负数，a == b 返回 0，a > b 返回正数
+	// multi 多重集合模式：允许重复元素，相等的元素按照插入顺序依次排列
+	multi bool
+	// augmenter 用户自定义的节点增强逻辑，为 nil 表示不需要维护
+	augmenter Augmenter[T]
+	// codec 元素的二进制编解码器，用于序列化
+	codec Codec[T]
+	// arena 节点分配器，为 nil 表示直接在堆上分配
+	arena *nodeArena[T]
+	// debug 调试模式：每次修改后校验红黑树性质，发现问题时 panic
+	debug bool
 }
 
 // NewRBTree 创建红黑树
 func NewRBTree[T cmp.Ordered]() *RBTree[T] {
 	return &RBTree[T]{
 		root: nil,
+		cmp:  cmp.Compare[T],
 	}
 }
 
-// IsValid 验证红黑树所有性质：
+// NewRBTreeFunc 使用自定义比较函数创建红黑树
 //
-// 1. 根节点为黑色
-// 2. 红色节点不能连续出现
-// 3. 所有路径黑高一致
-// 4. 叶子节点（NIL）视为黑色
+// cmp(a, b) 在 a < b 时返回负数，a == b 时返回 0，a > b 时返回正数，
+// 可用于结构体、逆序、忽略大小写等任意排序规则
+func NewRBTreeFunc[T any](cmp func(a, b T) int) *RBTree[T] {
+	return &RBTree[T]{
+		root: nil,
+		cmp:  cmp,
+	}
+}
+
+// IsValid 验证红黑树所有性质，详细的错误信息见 Check
 func (rb *RBTree[T]) IsValid() bool {
-	if rb.root == nil {
-		return true
+	return rb.Check() == nil
+}
+
+// Len 元素数量
+func (rb *RBTree[T]) Len() int {
+	return sizeOf(rb.root)
+}
+
+// Insert 插入，返回是否插入了新元素（元素已存在时返回 false）
+func (rb *RBTree[T]) Insert(val T) bool {
+	_, inserted := rb.insert(val)
+	return inserted
+}
+
+// Replace 使用 val 替换与其相等的元素，元素不存在时插入
+//
+// 返回被替换的旧值，以及元素是否已经存在；多重集合模式下替换最先插入的相等元素
+func (rb *RBTree[T]) Replace(val T) (T, bool) {
+	node := rb.find(val)
+	if node == nil {
+		rb.insert(val)
+		return *new(T), false
+	}
+	old := node.val
+	node.val = val
+	// 值改变后，路径上的增强值需要重新计算
+	for cur := node; cur != nil; cur = cur.parent {
+		cur.update(rb.augmenter)
 	}
-	if rb.root.color != black {
+	return old, true
+}
+
+// Find 查找，返回元素的句柄，元素不存在时返回 nil
+//
+// 多重集合模式下返回最先插入的相等元素
+func (rb *RBTree[T]) Find(val T) *Handle[T] {
+	return rb.handleOf(rb.find(val))
+}
+
+// Delete 删除，返回是否删除了元素（元素不存在时返回 false）
+//
+// 多重集合模式下只删除一个元素，等价于 DeleteOne
+func (rb *RBTree[T]) Delete(val T) bool {
+	del := rb.find(val)
+	if del == nil {
 		return false
 	}
-	_, ok := rb.root.verifyBlackHeightAndRedRules()
-	return ok
+	rb.deleteNode(del)
+	return true
 }
 
-// Insert 插入
-func (rb *RBTree[T]) Insert(val T) error {
-	newNode := newRBNode(val, black)
+// -------------------------------------------------------------------
+// Internal method
+// -------------------------------------------------------------------
+
+// find 查找值对应的节点
+//
+// 多重集合模式下返回最先插入的相等元素
+func (rb *RBTree[T]) find(val T) *rbNode[T] {
+	if rb.multi {
+		node := rb.lowerBound(val)
+		if node != nil && rb.cmp(node.val, val) == 0 {
+			return node
+		}
+		return nil
+	}
+	cur := rb.root
+	for cur != nil {
+		switch c := rb.cmp(val, cur.val); {
+		case c < 0:
+			cur = cur.left
+		case c == 0:
+			return cur
+		default:
+			cur = cur.right
+		}
+	}
+	return nil
+}
+
+// insert 插入节点
+//
+// 返回值对应的节点，以及是否为新插入的节点（false 表示元素已存在）
+func (rb *RBTree[T]) insert(val T) (*rbNode[T], bool) {
+	newNode := rb.newNode(val, black)
 	if rb.root == nil {
 		rb.root = newNode
-		return nil
+		return newNode, true
 	}
 	var (
 		cur    = rb.root
 		parent *rbNode[T]
+		isLeft bool
 	)
 	for cur != nil {
-		switch cmp.Compare(val, cur.val) {
-		case -1:
-			parent = cur
+		switch c := rb.cmp(val, cur.val); {
+		case c < 0:
+			parent, isLeft = cur, true
 			cur = cur.left
-		case 0:
+		case c == 0 && !rb.multi:
 			// 重复元素，无需插入
-			return nil
-		case +1:
-			parent = cur
+			rb.freeNode(newNode)
+			return cur, false
+		default:
+			// 多重集合模式下，相等的元素插入到右子树，保持插入顺序
+			parent, isLeft = cur, false
 			cur = cur.right
 		}
 	}
 	newNode.color = red
 	newNode.parent = parent
-	res := cmp.Compare(newNode.val, parent.val)
-	if res == -1 {
+	if isLeft {
 		parent.left = newNode
-	} else if res == +1 {
-		parent.right = newNode
 	} else {
-		return errors.ErrUnsupported
+		parent.right = newNode
 	}
-	return rb.fixInsertion(newNode)
-}
-
-// Find 查找
-func (rb *RBTree[T]) Find(val T) *rbNode[T] {
-	cur := rb.root
-	for cur != nil {
-		switch cmp.Compare(val, cur.val) {
-		case -1:
-			cur = cur.left
-		case 0:
-			return cur
-		case +1:
-			cur = cur.right
-		}
+	// 自底向上更新插入路径上的祖先节点，旋转时会自行维护
+	for cur := parent; cur != nil; cur = cur.parent {
+		cur.update(rb.augmenter)
 	}
-	return nil
+	rb.validate(rb.fixInsertion(newNode))
+	return newNode, true
 }
 
-// Delete 删除
-func (rb *RBTree[T]) Delete(val T) error {
-	if rb.root == nil {
-		return nil
-	}
-
-	del := rb.Find(val)
-	if del == nil {
-		return nil
-	}
+// deleteNode 从树上删除节点 del 并回收
+func (rb *RBTree[T]) deleteNode(del *rbNode[T]) {
+	rb.unlink(del)
+	rb.freeNode(del)
+}
 
+// unlink 从树上摘下节点 del，不回收节点
+//
+// del 有两个孩子时，把右子树最小的节点整体移动到 del 的位置，而不是只拷贝值，
+// 这样其他节点的句柄在删除后仍然有效
+func (rb *RBTree[T]) unlink(del *rbNode[T]) {
 	var (
-		// delColor 删除节点的颜色
+		// delColor 实际被摘除位置的颜色
 		delColor = del.color
 		// n 是替代节点，p 是替代节点的父节点
 		n, p *rbNode[T]
@@ -114,29 +193,65 @@ func (rb *RBTree[T]) Delete(val T) error {
 		p = del.parent
 		rb.transplant(del, del.left)
 	} else {
-		minRight := del.right.minSubNode()
-		del.val = minRight.val // 注意：只改变值，不改变颜色
-
 		// 替代为删除右子树最小的节点
+		minRight := del.right.minSubNode()
 		delColor = minRight.color
 		n = minRight.right
-		p = minRight.parent
-		rb.transplant(minRight, minRight.right)
+		if minRight.parent == del {
+			p = minRight
+		} else {
+			p = minRight.parent
+			rb.transplant(minRight, minRight.right)
+			minRight.right = del.right
+			minRight.right.parent = minRight
+		}
+		rb.transplant(del, minRight)
+		minRight.left = del.left
+		minRight.left.parent = minRight
+		minRight.color = del.color // 注意：继承被删除节点的颜色
+
+		// minRight 接管了 del 的孩子，从 p 开始重新计算子树大小以及增强值
+		for cur := p; cur != nil; cur = cur.parent {
+			cur.update(rb.augmenter)
+		}
 	}
 
+	var err error
 	if delColor == black {
-		return rb.fixDeletion(n, p)
+		err = rb.fixDeletion(n, p)
 	}
-	return nil
+	rb.validate(err)
 }
 
-// -------------------------------------------------------------------
-// Internal method
-// -------------------------------------------------------------------
+// newNode 创建属于当前红黑树的节点，开启 arena 时从 arena 中分配
+func (rb *RBTree[T]) newNode(val T, color rbColor) *rbNode[T] {
+	var node *rbNode[T]
+	if rb.arena != nil {
+		node = rb.arena.alloc()
+		node.val, node.color, node.size = val, color, 1
+	} else {
+		node = newRBNode(val, color)
+	}
+	if rb.augmenter != nil {
+		node.update(rb.augmenter)
+	}
+	return node
+}
+
+// freeNode 回收已经从树上摘下的节点，未开启 arena 时交给 GC 处理
+//
+// 回收前递增 gen，指向该节点的句柄全部失效，即使节点之后被 arena 复用也不会误用
+func (rb *RBTree[T]) freeNode(node *rbNode[T]) {
+	node.gen++
+	if rb.arena != nil {
+		rb.arena.free(node)
+	}
+}
 
 // transplant 使用 target 节点替代 src 节点
 //
-// src 的子节点不会在这个函数中继承给 target
+// src 的子节点不会在这个函数中继承给 target；
+// 替换后会重新计算 src 所有祖先节点的子树大小以及增强值
 func (rb *RBTree[T]) transplant(src, target *rbNode[T]) {
 	// 父节点绑定给 target
 	if src.parent == nil {
@@ -151,15 +266,27 @@ func (rb *RBTree[T]) transplant(src, target *rbNode[T]) {
 	if target != nil {
 		target.parent = src.parent
 	}
+
+	for cur := src.parent; cur != nil; cur = cur.parent {
+		cur.update(rb.augmenter)
+	}
 }
 
 // fixInsertion 处理插入后不平衡情况
 func (rb *RBTree[T]) fixInsertion(node *rbNode[T]) error {
+	_, err := rb.fixInsertionGrow(node)
+	return err
+}
+
+// fixInsertionGrow 处理插入后不平衡情况，返回整棵树的黑高是否增加了 1
+//
+// 只有情况 2 向上传递到根节点、把根节点染成红色时，重新染黑根节点才会使黑高增加
+func (rb *RBTree[T]) fixInsertionGrow(node *rbNode[T]) (bool, error) {
 	cur := node
 	if cur.parent == nil {
-		return nil
+		return false, nil
 	} else if cur.parent.color == black {
-		return nil
+		return false, nil
 	}
 
 	// parent 是红色的话，一定会有祖父节点，因为红色无法作为根节点
@@ -192,25 +319,26 @@ func (rb *RBTree[T]) fixInsertion(node *rbNode[T]) error {
 			// 情况 4：父节点为红色，叔父节点为黑色，插入节点是 RL
 			fixFunc = fixInsertionCase4RL
 		default:
-			return errors.New("invalid case")
+			return false, errors.New("invalid case")
 		}
 		// 注意点 1：先判断再调用 Fix 函数，否则调用 Fix 函数后，节点的关系就变了 gp 的 parent 就不是以前的 parent 了
-		// 注意点 2：这里还要先缓存 gp.parent，因为gp.parent.right = fixFunc(cur) 是先执行 fixFunc 在赋值，此时 gp.parent 可能已经变了
+		// 注意点 2：这里还要先缓存 gp.parent，因为gp.parent.right = fixFunc(cur, rb.augmenter) 是先执行 fixFunc 在赋值，此时 gp.parent 可能已经变了
 		ggp := gp.parent
 		if ggp == nil {
-			rb.root = fixFunc(cur)
+			rb.root = fixFunc(cur, rb.augmenter)
 		} else if ggp.left == gp {
-			ggp.left = fixFunc(cur)
+			ggp.left = fixFunc(cur, rb.augmenter)
 		} else if ggp.right == gp {
-			ggp.right = fixFunc(cur)
+			ggp.right = fixFunc(cur, rb.augmenter)
 		} else {
-			return errors.New("gp parent is invalid")
+			return false, errors.New("gp parent is invalid")
 		}
 		break
 	}
 	// 注意：情况 2 可能把根节点染成红色，所以这里要处理下
+	grown := rb.root.color == red
 	rb.root.color = black
-	return nil
+	return grown, nil
 }
 
 // fixDeletion 处理插入后不平衡的情况。
@@ -236,11 +364,11 @@ func (rb *RBTree[T]) fixDeletion(n, p *rbNode[T]) error {
 		} else if bro.color == red {
 			// 情况 2:兄弟节点为红色
 			if gp == nil {
-				rb.root = fixDeletionCase2(cur, parent)
+				rb.root = fixDeletionCase2(cur, parent, rb.augmenter)
 			} else if isGPLeft {
-				gp.left = fixDeletionCase2(cur, parent)
+				gp.left = fixDeletionCase2(cur, parent, rb.augmenter)
 			} else {
-				gp.right = fixDeletionCase2(cur, parent)
+				gp.right = fixDeletionCase2(cur, parent, rb.augmenter)
 			}
 			// ⚠️注意点：旋转后替代节点跟父节点虽然没有变，但是兄弟节点改变了！
 			bro, isBroLeft = getBrother(cur, parent)
@@ -260,10 +388,10 @@ func (rb *RBTree[T]) fixDeletion(n, p *rbNode[T]) error {
 			} else if (isBroLeft && slBlack && !srBlack) ||
 				(!isBroLeft && srBlack && !slBlack) {
 				// 情况 3.3 远侄子黑，近侄子红
-				fixNode = fixDeletionCase3_3(cur, parent)
+				fixNode = fixDeletionCase3_3(cur, parent, rb.augmenter)
 			} else if (isBroLeft && !slBlack) || (!isBroLeft && !srBlack) {
 				// 情况 3.4 远侄子红，其他随意
-				fixNode = fixDeletionCase3_4(cur, parent)
+				fixNode = fixDeletionCase3_4(cur, parent, rb.augmenter)
 			}
 
 			if gp == nil {
@@ -1,7 +1,9 @@
 package rbtree
 
 import (
+	"cmp"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -318,7 +320,7 @@ func TestInsert(t *testing.T) {
 		data := GenBFSList()
 		rb := NewRBTree[int]()
 		for idx := range data {
-			require.NoError(t, rb.Insert(data[idx]))
+			rb.Insert(data[idx])
 		}
 		_, ok := rb.root.verifyBlackHeightAndRedRules()
 		require.True(t, ok)
@@ -517,13 +519,13 @@ func TestDelete(t *testing.T) {
 		data := GenBFSList()
 		rb := NewRBTree[int]()
 		for idx := range data {
-			require.NoError(t, rb.Insert(data[idx]))
+			rb.Insert(data[idx])
 		}
 
 		delCnt := len(data) / 10
 		for range delCnt {
 			i := rd.Intn(delCnt)
-			require.NoError(t, rb.Delete(data[i]))
+			rb.Delete(data[i])
 		}
 
 		_, ok := rb.root.verifyBlackHeightAndRedRules()
@@ -531,3 +533,95 @@ func TestDelete(t *testing.T) {
 		require.True(t, rb.IsValid())
 	}
 }
+
+func TestNewRBTreeFunc(t *testing.T) {
+	t.Run("Reverse", func(t *testing.T) {
+		data := GenBFSList()
+		rb := NewRBTreeFunc(func(a, b int) int { return b - a })
+		for idx := range data {
+			rb.Insert(data[idx])
+		}
+		require.True(t, rb.IsValid())
+		for idx := range data {
+			require.NotNil(t, rb.Find(data[idx]))
+		}
+		// 逆序：根节点左子树的值都比根节点大
+		if rb.root.left != nil {
+			require.Greater(t, rb.root.left.val, rb.root.val)
+		}
+	})
+
+	t.Run("CaseInsensitive", func(t *testing.T) {
+		rb := NewRBTreeFunc(func(a, b string) int {
+			return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+		})
+		rb.Insert("Go")
+		rb.Insert("GO")
+		rb.Insert("rust")
+		require.NotNil(t, rb.Find("go"))
+		require.Equal(t, "Go", rb.Find("gO").Value())
+		rb.Delete("RUST")
+		require.Nil(t, rb.Find("rust"))
+		require.True(t, rb.IsValid())
+	})
+
+	t.Run("CompositeKey", func(t *testing.T) {
+		type key struct {
+			group string
+			id    int
+		}
+		rb := NewRBTreeFunc(func(a, b key) int {
+			if c := strings.Compare(a.group, b.group); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.id, b.id)
+		})
+		data := GenUniqList()
+		for idx := range data {
+			rb.Insert(key{group: "a", id: data[idx]})
+			rb.Insert(key{group: "b", id: data[idx]})
+		}
+		require.True(t, rb.IsValid())
+		for idx := range data {
+			require.NotNil(t, rb.Find(key{group: "a", id: data[idx]}))
+			rb.Delete(key{group: "a", id: data[idx]})
+			require.Nil(t, rb.Find(key{group: "a", id: data[idx]}))
+			require.NotNil(t, rb.Find(key{group: "b", id: data[idx]}))
+		}
+		require.True(t, rb.IsValid())
+	})
+}
+
+func TestInsertDeleteResult(t *testing.T) {
+	rb := NewRBTree[int]()
+	data := GenUniqList()
+	for idx := range data {
+		require.True(t, rb.Insert(data[idx]))
+		require.False(t, rb.Insert(data[idx]))
+	}
+	require.Equal(t, len(data), rb.Len())
+
+	for idx := range data {
+		require.True(t, rb.Delete(data[idx]))
+		require.False(t, rb.Delete(data[idx]))
+	}
+	require.Equal(t, 0, rb.Len())
+	require.True(t, rb.IsValid())
+}
+
+func TestReplace(t *testing.T) {
+	rb := NewRBTreeFunc(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	old, replaced := rb.Replace("Go")
+	require.False(t, replaced)
+	require.Zero(t, old)
+	require.Equal(t, 1, rb.Len())
+
+	old, replaced = rb.Replace("GO")
+	require.True(t, replaced)
+	require.Equal(t, "Go", old)
+	require.Equal(t, "GO", rb.Find("go").Value())
+	require.Equal(t, 1, rb.Len())
+	require.True(t, rb.IsValid())
+}
@@ -1,14 +1,12 @@
 package rbtree
 
-import "cmp"
-
-// FixFunc 修复红黑树的函数定义
-type FixFunc[T cmp.Ordered] func(*rbNode[T]) *rbNode[T]
+// FixFunc 修复红黑树的函数定义，aug 为红黑树的增强逻辑，旋转时用于维护增强值
+type FixFunc[T any] func(node *rbNode[T], aug Augmenter[T]) *rbNode[T]
 
 // rotateRight 右旋
 //
 // 返回旋转后新的根节点(需要将原先的父节点的孩子设置成新返回的节点)
-func rotateRight[T cmp.Ordered](root *rbNode[T]) *rbNode[T] {
+func rotateRight[T any](root *rbNode[T], aug Augmenter[T]) *rbNode[T] {
 	newRoot := root.left
 	root.left = newRoot.right
 	if newRoot.right != nil {
@@ -17,13 +15,16 @@ func rotateRight[T cmp.Ordered](root *rbNode[T]) *rbNode[T] {
 	newRoot.parent = root.parent
 	newRoot.right = root
 	root.parent = newRoot
+	// 先更新子节点，再更新新的根节点（子树大小以及用户自定义的增强值）
+	root.update(aug)
+	newRoot.update(aug)
 	return newRoot
 }
 
 // rotateLeft 左旋
 //
 // 返回旋转后新的根节点(需要将原先的父节点的孩子设置成新返回的节点)
-func rotateLeft[T cmp.Ordered](root *rbNode[T]) *rbNode[T] {
+func rotateLeft[T any](root *rbNode[T], aug Augmenter[T]) *rbNode[T] {
 	newRoot := root.right
 	root.right = newRoot.left
 	if newRoot.left != nil {
@@ -32,11 +33,14 @@ func rotateLeft[T cmp.Ordered](root *rbNode[T]) *rbNode[T] {
 	newRoot.parent = root.parent
 	newRoot.left = root
 	root.parent = newRoot
+	// 先更新子节点，再更新新的根节点（子树大小以及用户自定义的增强值）
+	root.update(aug)
+	newRoot.update(aug)
 	return newRoot
 }
 
 // isBlack 是否是黑色
-func isBlack[T cmp.Ordered](node *rbNode[T]) bool {
+func isBlack[T any](node *rbNode[T]) bool {
 	if node == nil {
 		return true
 	}
@@ -54,7 +58,7 @@ func isBlack[T cmp.Ordered](node *rbNode[T]) bool {
 // 1. 将父节点变成黑色，祖父节点变成红色
 // 2. 将祖父节点右旋
 // 返回旋转后新的根节点
-func fixInsertionCase3LL[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
+func fixInsertionCase3LL[T any](node *rbNode[T], aug Augmenter[T]) *rbNode[T] {
 	// 情况 3 一定会有祖父节点，因为父节点是红色不可能作为根节点
 	var (
 		parent = node.parent
@@ -62,7 +66,7 @@ func fixInsertionCase3LL[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
 	)
 	parent.color = black
 	grand.color = red
-	return rotateRight(grand)
+	return rotateRight(grand, aug)
 }
 
 // fixInsertionCase3RR 处理情况3：父节点为红色，叔父节点为黑色；插入节点是 RR 型
@@ -76,7 +80,7 @@ func fixInsertionCase3LL[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
 // 1. 将父节点变成黑色，祖父节点变成红色
 // 2. 将祖父节点左旋
 // 返回旋转后新的根节点
-func fixInsertionCase3RR[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
+func fixInsertionCase3RR[T any](node *rbNode[T], aug Augmenter[T]) *rbNode[T] {
 	// 情况 3 一定会有祖父节点，因为父节点是红色不可能作为根节点
 	var (
 		parent = node.parent
@@ -84,7 +88,7 @@ func fixInsertionCase3RR[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
 	)
 	parent.color = black
 	grand.color = red
-	return rotateLeft(grand)
+	return rotateLeft(grand, aug)
 }
 
 // fixInsertionCase4LR 处理情况4: 父节点为红色，叔父节点为黑色；插入节点是 LR 型
@@ -98,17 +102,17 @@ func fixInsertionCase3RR[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
 // 1. 对父节点左旋
 // 2. 处理节点变成父节点 -> 转换成情况 3 LL
 // 返回旋转后的新根节点
-func fixInsertionCase4LR[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
+func fixInsertionCase4LR[T any](node *rbNode[T], aug Augmenter[T]) *rbNode[T] {
 	var (
 		parent = node.parent
 		grand  = parent.parent
 	)
 	if grand.left == parent {
-		grand.left = rotateLeft(parent)
+		grand.left = rotateLeft(parent, aug)
 	} else {
-		grand.right = rotateLeft(parent)
+		grand.right = rotateLeft(parent, aug)
 	}
-	return fixInsertionCase3LL(parent)
+	return fixInsertionCase3LL(parent, aug)
 }
 
 // fixInsertionCase4RL 处理情况4: 父节点为红色，叔父节点为黑色；插入节点是 RL 型
@@ -116,17 +120,17 @@ func fixInsertionCase4LR[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
 // 1. 对父节点右旋
 // 2. 处理节点变成父节点 -> 转换成情况 3 RR
 // 返回旋转后的新根节点
-func fixInsertionCase4RL[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
+func fixInsertionCase4RL[T any](node *rbNode[T], aug Augmenter[T]) *rbNode[T] {
 	var (
 		parent = node.parent
 		grand  = parent.parent
 	)
 	if grand.left == parent {
-		grand.left = rotateRight(parent)
+		grand.left = rotateRight(parent, aug)
 	} else {
-		grand.right = rotateRight(parent)
+		grand.right = rotateRight(parent, aug)
 	}
-	return fixInsertionCase3RR(parent)
+	return fixInsertionCase3RR(parent, aug)
 }
 
 // --------------------------------------------------------
@@ -135,7 +139,7 @@ func fixInsertionCase4RL[T cmp.Ordered](node *rbNode[T]) *rbNode[T] {
 // getBrother 获取 n 的兄弟节点（n 可能为空）
 //
 // 返回兄弟节点，以及兄弟节点在左还是右
-func getBrother[T cmp.Ordered](n, p *rbNode[T]) (bro *rbNode[T], isLeft bool) {
+func getBrother[T any](n, p *rbNode[T]) (bro *rbNode[T], isLeft bool) {
 	if p == nil {
 		return nil, false
 	}
@@ -157,14 +161,14 @@ func getBrother[T cmp.Ordered](n, p *rbNode[T]) (bro *rbNode[T], isLeft bool) {
 //	  SL(B) SR(B)        N(?) SL(B)
 //
 // 返回旋转后的新的根节点
-func fixDeletionCase2[T cmp.Ordered](n, p *rbNode[T]) (newp *rbNode[T]) {
+func fixDeletionCase2[T any](n, p *rbNode[T], aug Augmenter[T]) (newp *rbNode[T]) {
 	bro, isLeft := getBrother(n, p) // 不可能为空
 	bro.color = black
 	p.color = red
 	if isLeft {
-		newp = rotateRight(p)
+		newp = rotateRight(p, aug)
 	} else {
-		newp = rotateLeft(p)
+		newp = rotateLeft(p, aug)
 	}
 	return newp
 }
@@ -178,7 +182,7 @@ func fixDeletionCase2[T cmp.Ordered](n, p *rbNode[T]) (newp *rbNode[T]) {
 //	  SL(B) SR(B)                 SL(B) SR(B)
 //
 // 返回新的替代节点
-func fixDeletionCase3_1[T cmp.Ordered](n, p *rbNode[T]) (newn *rbNode[T]) {
+func fixDeletionCase3_1[T any](n, p *rbNode[T]) (newn *rbNode[T]) {
 	bro, _ := getBrother(n, p)
 	bro.color = red
 	newn = p
@@ -192,7 +196,7 @@ func fixDeletionCase3_1[T cmp.Ordered](n, p *rbNode[T]) (newn *rbNode[T]) {
 //	N(B) S(B)   ------>         N(B) S(R)
 //	     / \                         / \
 //	  SL(B) SR(B)                 SL(B) SR(B)
-func fixDeletionCase3_2[T cmp.Ordered](n, p *rbNode[T]) {
+func fixDeletionCase3_2[T any](n, p *rbNode[T]) {
 	bro, _ := getBrother(n, p)
 	bro.color = red
 	p.color = black
@@ -209,17 +213,17 @@ func fixDeletionCase3_2[T cmp.Ordered](n, p *rbNode[T]) {
 //	                                                            SR(B)
 //
 // 转换成情况 3.4（还有 N(B)位于右子树的情况，旋转方向改变即可）
-func fixDeletionCase3_3[T cmp.Ordered](n, p *rbNode[T]) (newp *rbNode[T]) {
+func fixDeletionCase3_3[T any](n, p *rbNode[T], aug Augmenter[T]) (newp *rbNode[T]) {
 	bro, isLeft := getBrother(n, p)
 	bro.color = red
 	if isLeft {
 		bro.right.color = black
-		p.left = rotateLeft(bro)
+		p.left = rotateLeft(bro, aug)
 	} else {
 		bro.left.color = black
-		p.right = rotateRight(bro)
+		p.right = rotateRight(bro, aug)
 	}
-	return fixDeletionCase3_4(n, p)
+	return fixDeletionCase3_4(n, p, aug)
 }
 
 // fixDeletionCase3_4 处理情况 3.4：兄弟节点为黑色，远侄子为红色，P跟近侄子随意
@@ -231,7 +235,7 @@ func fixDeletionCase3_3[T cmp.Ordered](n, p *rbNode[T]) (newp *rbNode[T]) {
 //	  SL(?) SR(R)                 SL(?) SR(B)           N(?) SL(?)
 //
 // 返回旋转后的新根节点（还有 N(B) 位于右子树的情况，旋转方向改变即可）
-func fixDeletionCase3_4[T cmp.Ordered](n, p *rbNode[T]) (newp *rbNode[T]) {
+func fixDeletionCase3_4[T any](n, p *rbNode[T], aug Augmenter[T]) (newp *rbNode[T]) {
 	bro, isLeft := getBrother(n, p)
 	bro.color = p.color
 	p.color = black
@@ -239,9 +243,9 @@ func fixDeletionCase3_4[T cmp.Ordered](n, p *rbNode[T]) (newp *rbNode[T]) {
 	// 远侄子变黑色
 	if isLeft {
 		bro.left.color = black
-		return rotateRight(p)
+		return rotateRight(p, aug)
 	} else {
 		bro.right.color = black
-		return rotateLeft(p)
+		return rotateLeft(p, aug)
 	}
 }
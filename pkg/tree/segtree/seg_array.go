@@ -0,0 +1,163 @@
+// 基于数组的线段树定义
+package segtree
+
+import "fmt"
+
+// ArraySegTree 基于数组（堆式存储）的线段树，自顶向下递归查询与修改
+//
+// 节点编号从 1 开始，对于编号为 i 的节点，左孩子编号为 2i，右孩子为 2i+1；
+// 节点只存储聚合值，区间由递归参数决定，数组长度为 4n
+type ArraySegTree[T any] struct {
+	tree []T
+	m    Monoid[T]
+	n    int // 原始序列的长度
+}
+
+// NewArraySegTree 构建基于数组的线段树，时间复杂度 O(n)
+func NewArraySegTree[T any](seg []T, m Monoid[T]) *ArraySegTree[T] {
+	st := &ArraySegTree[T]{
+		m: m,
+		n: len(seg),
+	}
+	if st.n > 0 {
+		st.tree = make([]T, 4*st.n)
+		st.build(seg, 1, 0, st.n)
+	}
+	return st
+}
+
+// Len 原始序列的长度
+func (st *ArraySegTree[T]) Len() int {
+	return st.n
+}
+
+// Query 查询区间 [l, r) 的聚合值
+func (st *ArraySegTree[T]) Query(l, r int) (T, error) {
+	if l < 0 || r > st.n || l >= r {
+		return *new(T), fmt.Errorf("%w: [%d, %d)", ErrNotInRang, l, r)
+	}
+	return st.query(1, 0, st.n, l, r), nil
+}
+
+// Update 单点修改，将第 i 个元素修改为 v，时间复杂度 O(log n)
+func (st *ArraySegTree[T]) Update(i int, v T) error {
+	if i < 0 || i >= st.n {
+		return fmt.Errorf("%w: index %d", ErrNotInRang, i)
+	}
+	st.update(1, 0, st.n, i, v)
+	return nil
+}
+
+// build 构建编号为 node 的节点，对应区间 [start, end)
+func (st *ArraySegTree[T]) build(seg []T, node, start, end int) {
+	if end-start == 1 {
+		// 叶子节点
+		st.tree[node] = seg[start]
+		return
+	}
+	mid := (start + end) >> 1
+	st.build(seg, node<<1, start, mid)
+	st.build(seg, node<<1|1, mid, end)
+	st.tree[node] = st.m.Combine(st.tree[node<<1], st.tree[node<<1|1])
+}
+
+// query 区间查询（递归），[l, r) 一定与节点区间 [start, end) 有交集
+func (st *ArraySegTree[T]) query(node, start, end, l, r int) T {
+	if l <= start && end <= r {
+		return st.tree[node]
+	}
+	mid := (start + end) >> 1
+	if r <= mid {
+		return st.query(node<<1, start, mid, l, r)
+	} else if l >= mid {
+		return st.query(node<<1|1, mid, end, l, r)
+	}
+	return st.m.Combine(st.query(node<<1, start, mid, l, r), st.query(node<<1|1, mid, end, l, r))
+}
+
+// update 单点修改（递归），回溯时重新计算路径上的聚合值
+func (st *ArraySegTree[T]) update(node, start, end, i int, v T) {
+	if end-start == 1 {
+		st.tree[node] = v
+		return
+	}
+	mid := (start + end) >> 1
+	if i < mid {
+		st.update(node<<1, start, mid, i, v)
+	} else {
+		st.update(node<<1|1, mid, end, i, v)
+	}
+	st.tree[node] = st.m.Combine(st.tree[node<<1], st.tree[node<<1|1])
+}
+
+// IterSegTree 基于数组的非递归线段树，自底向上查询与修改
+//
+// 叶子节点存储在 tree[n, 2n)，编号为 i 的节点的孩子为 2i 和 2i+1，数组长度为 2n；
+// n 不是 2 的幂时部分内部节点没有实际意义，但不会被查询用到。
+// 查询时左右两侧分别累积结果，所以 Combine 不需要满足交换律，但 Identity 必须是真正的单位元
+type IterSegTree[T any] struct {
+	tree []T
+	m    Monoid[T]
+	n    int // 原始序列的长度
+}
+
+// NewIterSegTree 构建非递归线段树，时间复杂度 O(n)
+//
+// 查询时会合并单位元，m 为 AggMonoid 的适配时没有可靠的单位元，返回 ErrNoIdentity
+func NewIterSegTree[T any](seg []T, m Monoid[T]) (*IterSegTree[T], error) {
+	if _, ok := m.(aggMonoid[T]); ok {
+		return nil, ErrNoIdentity
+	}
+	n := len(seg)
+	st := &IterSegTree[T]{
+		tree: make([]T, 2*n),
+		m:    m,
+		n:    n,
+	}
+	copy(st.tree[n:], seg)
+	for i := n - 1; i > 0; i-- {
+		st.tree[i] = m.Combine(st.tree[i<<1], st.tree[i<<1|1])
+	}
+	return st, nil
+}
+
+// Len 原始序列的长度
+func (st *IterSegTree[T]) Len() int {
+	return st.n
+}
+
+// Query 查询区间 [l, r) 的聚合值
+func (st *IterSegTree[T]) Query(l, r int) (T, error) {
+	if l < 0 || r > st.n || l >= r {
+		return *new(T), fmt.Errorf("%w: [%d, %d)", ErrNotInRang, l, r)
+	}
+
+	// resl 累积左侧的结果，resr 累积右侧的结果
+	resl, resr := st.m.Identity(), st.m.Identity()
+	for l, r = l+st.n, r+st.n; l < r; l, r = l>>1, r>>1 {
+		if l&1 == 1 {
+			// l 是右孩子，父节点包含 l 左侧的区间，只能单独合并 l
+			resl = st.m.Combine(resl, st.tree[l])
+			l++
+		}
+		if r&1 == 1 {
+			// r 是右孩子，r-1 是左孩子并且位于区间内
+			r--
+			resr = st.m.Combine(st.tree[r], resr)
+		}
+	}
+	return st.m.Combine(resl, resr), nil
+}
+
+// Update 单点修改，将第 i 个元素修改为 v，时间复杂度 O(log n)
+func (st *IterSegTree[T]) Update(i int, v T) error {
+	if i < 0 || i >= st.n {
+		return fmt.Errorf("%w: index %d", ErrNotInRang, i)
+	}
+	i += st.n
+	st.tree[i] = v
+	for i >>= 1; i > 0; i >>= 1 {
+		st.tree[i] = st.m.Combine(st.tree[i<<1], st.tree[i<<1|1])
+	}
+	return nil
+}
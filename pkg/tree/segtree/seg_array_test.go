@@ -0,0 +1,106 @@
+package segtree
+
+import (
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// arrayTree 基于数组的线段树的公共方法
+type arrayTree[T any] interface {
+	Len() int
+	Query(l, r int) (T, error)
+	Update(i int, v T) error
+}
+
+// mustIterSegTree 构建非递归线段树，m 必须有可靠的单位元
+func mustIterSegTree[T any](t *testing.T, seg []T, m Monoid[T]) *IterSegTree[T] {
+	st, err := NewIterSegTree(seg, m)
+	require.NoError(t, err)
+	return st
+}
+
+func TestArraySegTree(t *testing.T) {
+	for _, st := range []arrayTree[int]{
+		NewArraySegTree([]int{}, SumMonoid[int]{}),
+		mustIterSegTree(t, []int{}, SumMonoid[int]{}),
+	} {
+		require.Equal(t, 0, st.Len())
+		_, err := st.Query(0, 1)
+		require.ErrorIs(t, err, ErrNotInRang)
+		require.ErrorIs(t, st.Update(0, 1), ErrNotInRang)
+	}
+
+	testcnt := 110
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	fArr := []AggFunc[int]{Sum[int], Max[int], Min[int]}
+	mArr := []Monoid[int]{SumMonoid[int]{}, MaxMonoid[int]{Lowest: math.MinInt}, MinMonoid[int]{Highest: math.MaxInt}}
+	for i := range testcnt {
+		cnt := i + 1
+		nums := GenNumList(cnt, 100000)
+		f, m := fArr[i%3], mArr[i%3]
+		sts := []arrayTree[int]{NewArraySegTree(nums, m), mustIterSegTree(t, nums, m)}
+		for _, st := range sts {
+			require.Equal(t, cnt, st.Len())
+			_, err := st.Query(0, cnt+1)
+			require.ErrorIs(t, err, ErrNotInRang)
+			_, err = st.Query(1, 1)
+			require.ErrorIs(t, err, ErrNotInRang)
+			require.ErrorIs(t, st.Update(cnt, 1), ErrNotInRang)
+		}
+
+		for range 100 {
+			idx, v := rd.IntN(cnt), rd.IntN(200000)-100000
+			nums[idx] = v
+			start := rd.IntN(cnt)
+			end := min(start+rd.IntN(cnt-start)+1, cnt)
+			for _, st := range sts {
+				require.NoError(t, st.Update(idx, v))
+				res, err := st.Query(start, end)
+				require.NoError(t, err)
+				require.Equal(t, f(nums[start:end]), res)
+			}
+		}
+	}
+}
+
+func TestArraySegTreeNonCommutative(t *testing.T) {
+	// 字符串拼接不满足交换律，检查合并的顺序
+	for cnt := 1; cnt <= 40; cnt++ {
+		strs := make([]string, cnt)
+		for i := range strs {
+			strs[i] = string(rune('a' + i%26))
+		}
+		sts := []arrayTree[string]{
+			NewArraySegTree(strs, SumMonoid[string]{}),
+			mustIterSegTree(t, strs, SumMonoid[string]{}),
+		}
+		for l := 0; l < cnt; l++ {
+			for r := l + 1; r <= cnt; r++ {
+				for _, st := range sts {
+					res, err := st.Query(l, r)
+					require.NoError(t, err)
+					require.Equal(t, strings.Join(strs[l:r], ""), res)
+				}
+			}
+		}
+	}
+}
+
+func TestArraySegTreeNoAlloc(t *testing.T) {
+	nums := GenNumList(1000, 100000)
+	for _, st := range []arrayTree[int]{
+		NewArraySegTree(nums, SumMonoid[int]{}),
+		mustIterSegTree(t, nums, SumMonoid[int]{}),
+	} {
+		allocs := testing.AllocsPerRun(100, func() {
+			st.Query(123, 877)
+			st.Update(500, 1)
+		})
+		require.Zero(t, allocs)
+	}
+}
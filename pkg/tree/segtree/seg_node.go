@@ -1,11 +1,149 @@
 // 线段树节点定义
 package segtree
 
-import "cmp"
+import (
+	"cmp"
+	"math"
+)
 
 // AggFunc 提供聚合功能的函数
 type AggFunc[T any] func([]T) T
 
+// Monoid 满足结合律并且有单位元的二元运算，用于计算区间的聚合值
+//
+// 节点的聚合值由左右孩子的聚合值两两合并得到，构建的时间复杂度为 O(n)，查询过程不需要分配内存
+type Monoid[T any] interface {
+	// Identity 单位元，满足 Combine(Identity(), a) == Combine(a, Identity()) == a
+	Identity() T
+	// Combine 合并相邻两个区间的聚合值，a 在左，b 在右
+	Combine(a, b T) T
+}
+
+// NewMonoid 使用单位元以及合并函数构建 Monoid，T 可以是任意类型
+//
+// 如区间 (最小值, 下标)、(和, 数量)、矩阵连乘等，combine 只需要满足结合律
+func NewMonoid[T any](identity T, combine func(a, b T) T) Monoid[T] {
+	return funcMonoid[T]{identity: identity, combine: combine}
+}
+
+// funcMonoid 函数形式的 Monoid
+type funcMonoid[T any] struct {
+	identity T
+	combine  func(a, b T) T
+}
+
+// Identity 实现 Monoid
+func (m funcMonoid[T]) Identity() T {
+	return m.identity
+}
+
+// Combine 实现 Monoid
+func (m funcMonoid[T]) Combine(a, b T) T {
+	return m.combine(a, b)
+}
+
+// AggMonoid 将 AggFunc 适配为 Monoid
+//
+// 以 f(nil) 作为单位元，Combine(a, b) 即 f([]T{a, b})，每次合并都会分配一个长度为 2 的切片；
+// f(nil) 不一定是真正的单位元，不能用于 IterSegTree。
+// 求和、最大值、最小值应当直接使用 SumMonoid、MaxMonoid、MinMonoid（见 NewSumSegTree 等）
+func AggMonoid[T any](f AggFunc[T]) Monoid[T] {
+	return aggMonoid[T]{f: f}
+}
+
+// aggMonoid AggFunc 到 Monoid 的适配器
+type aggMonoid[T any] struct {
+	f AggFunc[T]
+}
+
+// Identity 实现 Monoid
+func (m aggMonoid[T]) Identity() T {
+	return m.f(nil)
+}
+
+// Combine 实现 Monoid
+func (m aggMonoid[T]) Combine(a, b T) T {
+	return m.f([]T{a, b})
+}
+
+// SumMonoid 求和，对应 Sum
+type SumMonoid[T cmp.Ordered] struct{}
+
+// Identity 零值
+func (SumMonoid[T]) Identity() T {
+	return *new(T)
+}
+
+// Combine a + b
+func (SumMonoid[T]) Combine(a, b T) T {
+	return a + b
+}
+
+// MaxMonoid 求最大值，对应 Max
+//
+// Lowest 作为单位元，应当不大于所有元素，零值时与 Max 对空序列的结果一致
+type MaxMonoid[T cmp.Ordered] struct {
+	Lowest T
+}
+
+// Identity 返回 Lowest
+func (m MaxMonoid[T]) Identity() T {
+	return m.Lowest
+}
+
+// Combine 两者中的最大值
+func (MaxMonoid[T]) Combine(a, b T) T {
+	return max(a, b)
+}
+
+// MinMonoid 求最小值，对应 Min
+//
+// Highest 作为单位元，应当不小于所有元素，零值时与 Min 对空序列的结果一致
+type MinMonoid[T cmp.Ordered] struct {
+	Highest T
+}
+
+// Identity 返回 Highest
+func (m MinMonoid[T]) Identity() T {
+	return m.Highest
+}
+
+// Combine 两者中的最小值
+func (MinMonoid[T]) Combine(a, b T) T {
+	return min(a, b)
+}
+
+// lowest 数值类型的最小值，浮点数为负无穷
+func lowest[T Number]() T {
+	var zero T
+	if isFloat[T]() {
+		return T(math.Inf(-1))
+	}
+	if zero-1 > zero {
+		// 无符号整数
+		return zero
+	}
+	return -highest[T]() - 1
+}
+
+// highest 数值类型的最大值，浮点数为正无穷
+func highest[T Number]() T {
+	if isFloat[T]() {
+		return T(math.Inf(1))
+	}
+	// 不断翻倍直到溢出，得到最高的非符号位 hi，最大值即 hi + (hi - 1)
+	hi := T(1)
+	for hi*2 > hi {
+		hi *= 2
+	}
+	return hi + (hi - 1)
+}
+
+// isFloat 是否为浮点数类型，整数除法会舍去小数部分
+func isFloat[T Number]() bool {
+	return T(1)/2 != 0
+}
+
 // Sum 求和
 func Sum[T cmp.Ordered](seg []T) T {
 	var sum T
@@ -15,10 +153,13 @@ func Sum[T cmp.Ordered](seg []T) T {
 	return sum
 }
 
-// Max 求最大值
+// Max 求最大值，空序列返回零值
 func Max[T cmp.Ordered](seg []T) T {
-	var max T
-	for _, v := range seg {
+	if len(seg) == 0 {
+		return *new(T)
+	}
+	max := seg[0]
+	for _, v := range seg[1:] {
 		if v > max {
 			max = v
 		}
@@ -26,10 +167,13 @@ func Max[T cmp.Ordered](seg []T) T {
 	return max
 }
 
-// Min 求最小值
+// Min 求最小值，空序列返回零值
 func Min[T cmp.Ordered](seg []T) T {
-	var min T
-	for _, v := range seg {
+	if len(seg) == 0 {
+		return *new(T)
+	}
+	min := seg[0]
+	for _, v := range seg[1:] {
 		if v < min {
 			min = v
 		}
@@ -37,12 +181,89 @@ func Min[T cmp.Ordered](seg []T) T {
 	return min
 }
 
-// 也可以采用堆式存储来构建，节点编号从 1 开始
+// Action 区间修改作用在聚合值上的方式，配合 AggFunc 或者 Monoid 实现懒标记
+//
+// 区间修改时只更新完全覆盖的节点的聚合值并记录懒标记，之后访问子节点时再下推，
+// 所以需要知道修改如何直接作用在整个区间的聚合值上，以及多次修改如何合并成一个标记
+type Action[T any] interface {
+	// Add 区间内每个元素都加上 delta 后的聚合值，agg 为原先的聚合值，n 为区间长度
+	Add(agg, delta T, n int) T
+	// Assign 区间内每个元素都赋值为 val 后的聚合值，n 为区间长度
+	Assign(val T, n int) T
+	// Compose 合并两次加法：先加上 a 再加上 b，等价于加上 Compose(a, b)
+	//
+	// 单个元素加上 delta 以及赋值后再加上 delta 时，通过 Add(val, delta, 1) 得到新值
+	Compose(a, b T) T
+}
+
+// Number 支持四则运算的数值类型
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// SumAction 配合 Sum 使用的区间修改
+type SumAction[T Number] struct{}
+
+// Add 区间和增加 delta * n
+func (SumAction[T]) Add(agg, delta T, n int) T {
+	return agg + delta*T(n)
+}
+
+// Assign 区间和为 val * n
+func (SumAction[T]) Assign(val T, n int) T {
+	return val * T(n)
+}
+
+// Compose 两次加法合并为一次
+func (SumAction[T]) Compose(a, b T) T {
+	return a + b
+}
+
+// MinMaxAction 配合 Max、Min 使用的区间修改
+type MinMaxAction[T Number] struct{}
+
+// Add 区间最值同样增加 delta
+func (MinMaxAction[T]) Add(agg, delta T, _ int) T {
+	return agg + delta
+}
+
+// Assign 区间最值为 val
+func (MinMaxAction[T]) Assign(val T, _ int) T {
+	return val
+}
+
+// Compose 两次加法合并为一次
+func (MinMaxAction[T]) Compose(a, b T) T {
+	return a + b
+}
+
+// tagKind 懒标记的类型
+type tagKind uint8
+
+const (
+	tagNone   tagKind = iota // 没有待下推的修改
+	tagAdd                   // 区间内每个元素加上 val
+	tagAssign                // 区间内每个元素赋值为 val
+)
+
+// lazyTag 懒标记，记录还没有下推给子节点的区间修改
+//
+// 赋值会覆盖之前的所有修改，赋值之后的加法直接合并到赋值中，
+// 所以一个标记最多只需要记录一次修改
+type lazyTag[T any] struct {
+	kind tagKind
+	val  T
+}
+
+// 也可以采用堆式存储来构建（见 ArraySegTree、IterSegTree），节点编号从 1 开始
 // 对于编号为 i 的节点，左孩子编号为 2i，右孩子为 2i+1
-type segNode[T cmp.Ordered] struct {
-	seg        []T // 原始序列
-	start, end int // 区间 [start, end)，左闭右开
-	aggVal     T   // 聚合值，表示区间和、区间最大值、区间最小值等，由聚合函数决定
+type segNode[T any] struct {
+	seg        []T        // 原始序列
+	start, end int        // 区间 [start, end)，左闭右开
+	aggVal     T          // 聚合值，表示区间和、区间最大值、区间最小值等，由聚合函数决定
+	tag        lazyTag[T] // 懒标记，aggVal 已经包含了标记的修改，子节点还没有
 	left       *segNode[T]
 	right      *segNode[T]
 }
@@ -51,13 +272,27 @@ type segNode[T cmp.Ordered] struct {
 //
 // seg 表示原始序列
 // l, r 表示区间 [l, r)，左闭右开
-func newSegNode[T cmp.Ordered](seg []T, l, r int, f AggFunc[T]) *segNode[T] {
-	return &segNode[T]{
-		seg:    seg,
-		start:  l,
-		end:    r,
-		aggVal: f(seg[l:r]),
+// 叶子节点的聚合值即为元素本身；非叶子节点的聚合值由左右孩子合并得到，需要在孩子构建完成后通过 pushUp 计算
+func newSegNode[T any](seg []T, l, r int) *segNode[T] {
+	node := &segNode[T]{
+		seg:   seg,
+		start: l,
+		end:   r,
 	}
+	if r-l == 1 {
+		node.aggVal = seg[l]
+	}
+	return node
+}
+
+// pushUp 合并左右孩子的聚合值
+func (s *segNode[T]) pushUp(m Monoid[T]) {
+	s.aggVal = m.Combine(s.left.aggVal, s.right.aggVal)
+}
+
+// isLeaf 是否为叶子节点
+func (s *segNode[T]) isLeaf() bool {
+	return s.left == nil || s.right == nil
 }
 
 // Segment 返回当前节点的区间
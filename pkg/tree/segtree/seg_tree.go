@@ -4,30 +4,142 @@ package segtree
 import (
 	"cmp"
 	"fmt"
+	"slices"
 )
 
 // SegTree 线段树定义
-type SegTree[T cmp.Ordered] struct {
+//
+// 元素可以是任意类型，如 (最小值, 下标)、(和, 数量) 或者矩阵，只需要提供对应的 Monoid
+type SegTree[T any] struct {
 	root *segNode[T]
-	f    AggFunc[T] // 聚合函数
+	m    Monoid[T]      // 聚合运算
+	act  Action[T]      // 区间修改，为 nil 表示不支持区间修改
+	add  func(a, b T) T // 单点加法，为 nil 表示不支持 Add
 	len  int
 }
 
-// NewSegTree 构建线段树
+// NewSegTree 使用聚合函数构建有序类型的线段树
+//
+// f 通过 AggMonoid 适配为 Monoid，每次合并都会分配内存；
+// 求和、最大值、最小值应当使用 NewSumSegTree、NewMaxSegTree、NewMinSegTree。
+// 线段树持有 seg 的副本，后续的修改不会影响传入的序列
 func NewSegTree[T cmp.Ordered](seg []T, f AggFunc[T]) *SegTree[T] {
+	st := NewSegTreeMonoid(seg, AggMonoid(f))
+	st.add = plus[T]
+	return st
+}
+
+// NewSumSegTree 构建区间求和的线段树，使用 SumMonoid
+func NewSumSegTree[T Number](seg []T) *SegTree[T] {
+	st := NewSegTreeMonoid[T](seg, SumMonoid[T]{})
+	st.add = plus[T]
+	return st
+}
+
+// NewMaxSegTree 构建区间最大值的线段树，使用 MaxMonoid，以元素类型的最小值作为单位元
+func NewMaxSegTree[T Number](seg []T) *SegTree[T] {
+	st := NewSegTreeMonoid[T](seg, MaxMonoid[T]{Lowest: lowest[T]()})
+	st.add = plus[T]
+	return st
+}
+
+// NewMinSegTree 构建区间最小值的线段树，使用 MinMonoid，以元素类型的最大值作为单位元
+func NewMinSegTree[T Number](seg []T) *SegTree[T] {
+	st := NewSegTreeMonoid[T](seg, MinMonoid[T]{Highest: highest[T]()})
+	st.add = plus[T]
+	return st
+}
+
+// NewSegTreeMonoid 使用 Monoid 构建任意类型的线段树
+//
+// 构建的时间复杂度为 O(n)，查询不需要分配内存；
+// 元素类型没有加法，不支持 Add，需要单点修改时使用 Update
+func NewSegTreeMonoid[T any](seg []T, m Monoid[T]) *SegTree[T] {
 	st := &SegTree[T]{
-		f: f,
+		m: m,
 	}
-	st.root, st.len = build(seg, f)
+	st.root, st.len = build(slices.Clone(seg), m)
 	return st
 }
 
-// Query 线段树的区间查询
+// NewLazySegTree 构建支持区间修改的有序类型线段树
+//
+// act 定义区间修改如何作用在 f 的聚合值上，如 Sum 对应 SumAction，Max、Min 对应 MinMaxAction；
+// f 通过 AggMonoid 适配，不需要分配内存时使用 NewLazySegTreeMonoid 配合 SumMonoid 等；
+// 与 NewLazySegTreeMonoid 相同，Add 使用 act.Add(old, delta, 1) 作为单点加法
+func NewLazySegTree[T cmp.Ordered](seg []T, f AggFunc[T], act Action[T]) *SegTree[T] {
+	return NewLazySegTreeMonoid(seg, AggMonoid(f), act)
+}
+
+// NewLazySegTreeMonoid 使用 Monoid 构建支持区间修改的任意类型线段树
+//
+// Add 使用 act.Add(old, delta, 1) 作为单点加法
+func NewLazySegTreeMonoid[T any](seg []T, m Monoid[T], act Action[T]) *SegTree[T] {
+	st := NewSegTreeMonoid(seg, m)
+	st.act = act
+	if act != nil {
+		st.add = func(old, delta T) T { return act.Add(old, delta, 1) }
+	}
+	return st
+}
+
+// Query 查询区间 [l, r) 的聚合值
 func (st *SegTree[T]) Query(l, r int) (T, error) {
-	if l < 0 || r >= st.len || l > r {
-		return *new(T), fmt.Errorf("invalid range [%d, %d)", l, r)
+	if st.root == nil || l < 0 || r > st.root.end || l >= r {
+		return *new(T), fmt.Errorf("%w: [%d, %d)", ErrNotInRang, l, r)
+	}
+	return query(st.root, l, r, st.m, st.act)
+}
+
+// Update 单点修改，将第 i 个元素修改为 v
+//
+// 只会重新计算叶子节点到根节点路径上的聚合值，时间复杂度 O(log n)
+func (st *SegTree[T]) Update(i int, v T) error {
+	if st.root == nil || i < 0 || i >= st.root.end {
+		return fmt.Errorf("%w: index %d", ErrNotInRang, i)
+	}
+	update(st.root, i, func(T) T { return v }, st.m, st.act)
+	return nil
+}
+
+// Add 单点修改，将第 i 个元素加上 delta，时间复杂度 O(log n)
+//
+// 需要通过 NewSegTree、NewSumSegTree 等有序类型的构造函数或者 NewLazySegTreeMonoid 构建，否则返回 ErrNoAdd
+func (st *SegTree[T]) Add(i int, delta T) error {
+	if st.add == nil {
+		return ErrNoAdd
+	}
+	if st.root == nil || i < 0 || i >= st.root.end {
+		return fmt.Errorf("%w: index %d", ErrNotInRang, i)
+	}
+	update(st.root, i, func(old T) T { return st.add(old, delta) }, st.m, st.act)
+	return nil
+}
+
+// RangeAdd 区间修改，将 [l, r) 内的每个元素加上 delta
+//
+// 借助懒标记，时间复杂度 O(log n)；需要通过 NewLazySegTree 构建
+func (st *SegTree[T]) RangeAdd(l, r int, delta T) error {
+	return st.rangeUpdate(l, r, lazyTag[T]{kind: tagAdd, val: delta})
+}
+
+// RangeAssign 区间修改，将 [l, r) 内的每个元素赋值为 val
+//
+// 借助懒标记，时间复杂度 O(log n)；需要通过 NewLazySegTree 构建
+func (st *SegTree[T]) RangeAssign(l, r int, val T) error {
+	return st.rangeUpdate(l, r, lazyTag[T]{kind: tagAssign, val: val})
+}
+
+// rangeUpdate 校验区间后执行区间修改
+func (st *SegTree[T]) rangeUpdate(l, r int, tag lazyTag[T]) error {
+	if st.act == nil {
+		return ErrNoAction
+	}
+	if st.root == nil || l < 0 || r > st.root.end || l >= r {
+		return fmt.Errorf("%w: [%d, %d)", ErrNotInRang, l, r)
 	}
-	return query(st.root, l, r, st.f)
+	rangeUpdate(st.root, l, r, tag, st.m, st.act)
+	return nil
 }
 
 // LevelOrder 层序遍历的结果
@@ -35,6 +147,7 @@ func (st *SegTree[T]) LevelOrder() [][]T {
 	if st.root == nil {
 		return nil
 	}
+	pushAll(st.root, st.act)
 
 	var (
 		queue = []*segNode[T]{st.root}
@@ -61,6 +174,7 @@ func (st *SegTree[T]) PreOrder() [][]T {
 	if st.root == nil {
 		return nil
 	}
+	pushAll(st.root, st.act)
 
 	var (
 		stack = make([]*segNode[T], 1, st.len)
@@ -89,6 +203,7 @@ func (st *SegTree[T]) InOrder() [][]T {
 	if st.root == nil {
 		return nil
 	}
+	pushAll(st.root, st.act)
 
 	var (
 		stack = make([]*segNode[T], 0, st.len)
@@ -117,6 +232,7 @@ func (st *SegTree[T]) PostOrder() [][]T {
 	if st.root == nil {
 		return nil
 	}
+	pushAll(st.root, st.act)
 
 	var (
 		stack                 = make([]*segNode[T], 0, st.len)
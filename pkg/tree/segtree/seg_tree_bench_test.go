@@ -29,3 +29,107 @@ func BenchmarkQuery(b *testing.B) {
 		st.Query(start, end)
 	}
 }
+
+func BenchmarkUpdate(b *testing.B) {
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	cnt := 100000
+	st := NewSegTree(GenNumList(cnt, 1000000), Sum)
+
+	b.ResetTimer()
+	for range b.N {
+		st.Update(rd.IntN(cnt), rd.IntN(1000000))
+	}
+}
+
+func BenchmarkRangeAdd(b *testing.B) {
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	cnt := 100000
+	st := NewLazySegTree(GenNumList(cnt, 1000000), Sum, SumAction[int]{})
+
+	b.ResetTimer()
+	for range b.N {
+		l := rd.IntN(cnt)
+		st.RangeAdd(l, l+rd.IntN(cnt-l)+1, rd.IntN(100))
+	}
+}
+
+func BenchmarkQueryMonoid(b *testing.B) {
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	cnt := 100000
+	sts := []*SegTree[int]{
+		NewSegTree(GenNumList(cnt, 1000000), Sum),
+		NewSegTreeMonoid(GenNumList(cnt, 1000000), SumMonoid[int]{}),
+	}
+	for idx, name := range []string{"AggFunc", "Monoid"} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				start := rd.IntN(cnt)
+				sts[idx].Query(start, start+rd.IntN(cnt-start)+1)
+			}
+		})
+	}
+}
+
+// benchTrees 参与对比的线段树实现，均使用 SumMonoid
+var benchTrees = []struct {
+	name  string
+	build func(nums []int) arrayTree[int]
+}{
+	{"Pointer", func(nums []int) arrayTree[int] { return pointerTree[int]{NewSegTreeMonoid(nums, SumMonoid[int]{})} }},
+	{"ArrayTopDown", func(nums []int) arrayTree[int] { return NewArraySegTree(nums, SumMonoid[int]{}) }},
+	{"ArrayBottomUp", func(nums []int) arrayTree[int] { st, _ := NewIterSegTree(nums, SumMonoid[int]{}); return st }},
+}
+
+// pointerTree 为基于指针的线段树补充 Len 方法，便于统一对比
+type pointerTree[T any] struct {
+	*SegTree[T]
+}
+
+// Len 原始序列的长度
+func (t pointerTree[T]) Len() int {
+	return t.root.end
+}
+
+func BenchmarkCompareBuild(b *testing.B) {
+	nums := GenNumList(100000, 1000000)
+	for _, bt := range benchTrees {
+		b.Run(bt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				bt.build(nums)
+			}
+		})
+	}
+}
+
+func BenchmarkCompareQuery(b *testing.B) {
+	rd := rand.New(rand.NewPCG(1, 2))
+	cnt := 100000
+	nums := GenNumList(cnt, 1000000)
+	for _, bt := range benchTrees {
+		st := bt.build(nums)
+		b.Run(bt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				start := rd.IntN(cnt)
+				st.Query(start, start+rd.IntN(cnt-start)+1)
+			}
+		})
+	}
+}
+
+func BenchmarkCompareUpdate(b *testing.B) {
+	rd := rand.New(rand.NewPCG(1, 2))
+	cnt := 100000
+	nums := GenNumList(cnt, 1000000)
+	for _, bt := range benchTrees {
+		st := bt.build(nums)
+		b.Run(bt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for range b.N {
+				st.Update(rd.IntN(cnt), rd.IntN(1000000))
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package segtree
 
 import (
 	"fmt"
+	"math"
 	"math/rand/v2"
 	"testing"
 	"time"
@@ -70,9 +71,392 @@ func TestQuery(t *testing.T) {
 		if start < end {
 			require.NoError(t, err)
 		} else {
-			require.ErrorIs(t, ErrNotInRang, err)
+			require.ErrorIs(t, err, ErrNotInRang)
 		}
 		require.Equal(t, ans, res)
 		fmt.Println("[INFO] test case:", i, "success, cost=", time.Since(begin).Milliseconds(), "ms, cnt=", cnt)
 	}
 }
+
+func TestQueryOutOfRange(t *testing.T) {
+	st := NewSegTree([]int{}, Sum)
+	_, err := st.Query(0, 1)
+	require.ErrorIs(t, err, ErrNotInRang)
+
+	for cnt := 1; cnt <= 20; cnt++ {
+		nums := GenNumList(cnt, 100)
+		st = NewSegTree(nums, Sum)
+		for _, rg := range [][2]int{{-1, 1}, {0, cnt + 1}, {cnt, cnt + 1}, {1, 1}, {1, 0}} {
+			_, err = st.Query(rg[0], rg[1])
+			require.ErrorIs(t, err, ErrNotInRang, "cnt: %d, range: %v", cnt, rg)
+		}
+		res, err := st.Query(0, cnt)
+		require.NoError(t, err)
+		require.Equal(t, Sum(nums), res)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	st := NewSegTree([]int{}, Sum)
+	require.ErrorIs(t, st.Update(0, 1), ErrNotInRang)
+	require.ErrorIs(t, st.Add(0, 1), ErrNotInRang)
+
+	// 修改不会影响传入的序列
+	nums := []int{1, 2, 3}
+	st = NewSegTree(nums, Sum)
+	require.NoError(t, st.Update(0, 10))
+	require.Equal(t, []int{1, 2, 3}, nums)
+	require.ErrorIs(t, st.Update(-1, 1), ErrNotInRang)
+	require.ErrorIs(t, st.Add(3, 1), ErrNotInRang)
+
+	testcnt := 110
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	fArr := []AggFunc[int]{Sum[int], Max[int], Min[int]}
+	for i := range testcnt {
+		cnt := min((i+1)*10, 1000)
+		nums := GenNumList(cnt, 100000)
+		f := fArr[i%3]
+		st := NewSegTree(nums, f)
+
+		for range 100 {
+			idx, v := rd.IntN(cnt), rd.IntN(100000)
+			if rd.IntN(2) == 0 {
+				nums[idx] = v
+				require.NoError(t, st.Update(idx, v))
+			} else {
+				nums[idx] += v
+				require.NoError(t, st.Add(idx, v))
+			}
+
+			// 修改后的区间查询与直接计算的聚合值一致
+			start := rd.IntN(cnt)
+			end := min(start+rd.IntN(cnt-start)+1, cnt)
+			res, err := st.Query(start, end)
+			require.NoError(t, err)
+			require.Equal(t, f(nums[start:end]), res)
+		}
+		require.Equal(t, f(nums), st.root.aggVal)
+	}
+}
+
+func TestRangeUpdate(t *testing.T) {
+	st := NewSegTree([]int{1, 2, 3}, Sum)
+	require.ErrorIs(t, st.RangeAdd(0, 1, 1), ErrNoAction)
+	require.ErrorIs(t, st.RangeAssign(0, 1, 1), ErrNoAction)
+
+	st = NewLazySegTree([]int{1, 2, 3}, Sum, SumAction[int]{})
+	require.ErrorIs(t, st.RangeAdd(-1, 1, 1), ErrNotInRang)
+	require.ErrorIs(t, st.RangeAdd(0, 4, 1), ErrNotInRang)
+	require.ErrorIs(t, st.RangeAssign(1, 1, 1), ErrNotInRang)
+
+	// 遍历结果包含懒标记中的修改
+	require.NoError(t, st.RangeAdd(0, 3, 10))
+	require.NoError(t, st.RangeAssign(0, 2, 5))
+	require.Equal(t, []int{5, 5, 13}, st.LevelOrder()[0])
+
+	testcnt := 110
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	fArr := []AggFunc[int]{Sum[int], Max[int], Min[int]}
+	actArr := []Action[int]{SumAction[int]{}, MinMaxAction[int]{}, MinMaxAction[int]{}}
+	for i := range testcnt {
+		cnt := min((i+1)*10, 1000)
+		nums := GenNumList(cnt, 1000)
+		f, act := fArr[i%3], actArr[i%3]
+		st := NewLazySegTree(nums, f, act)
+
+		for range 100 {
+			l := rd.IntN(cnt)
+			r := min(l+rd.IntN(cnt-l)+1, cnt)
+			v := rd.IntN(2000) - 1000
+			switch rd.IntN(4) {
+			case 0:
+				for j := l; j < r; j++ {
+					nums[j] += v
+				}
+				require.NoError(t, st.RangeAdd(l, r, v))
+			case 1:
+				for j := l; j < r; j++ {
+					nums[j] = v
+				}
+				require.NoError(t, st.RangeAssign(l, r, v))
+			case 2:
+				nums[l] = v
+				require.NoError(t, st.Update(l, v))
+			case 3:
+				nums[l] += v
+				require.NoError(t, st.Add(l, v))
+			}
+
+			// 修改后的区间查询与直接计算的聚合值一致
+			start := rd.IntN(cnt)
+			end := min(start+rd.IntN(cnt-start)+1, cnt)
+			res, err := st.Query(start, end)
+			require.NoError(t, err)
+			require.Equal(t, f(nums[start:end]), res)
+		}
+		require.Equal(t, nums, st.PreOrder()[0])
+	}
+}
+
+// mulAction 区间修改为乘上 delta，配合 Max 使用，元素均为正数
+type mulAction struct{}
+
+func (mulAction) Add(agg, delta int, _ int) int { return agg * delta }
+func (mulAction) Assign(val int, _ int) int     { return val }
+func (mulAction) Compose(a, b int) int          { return a * b }
+
+func TestLazyAddAction(t *testing.T) {
+	// 单点 Add 与长度为 1 的 RangeAdd 都通过 act.Add 作用在元素上
+	st := NewLazySegTree([]int{2, 3, 4}, Max, mulAction{})
+	require.NoError(t, st.Add(0, 10))
+	require.Equal(t, []int{20, 3, 4}, st.PreOrder()[0])
+
+	rangeSt := NewLazySegTree([]int{2, 3, 4}, Max, mulAction{})
+	require.NoError(t, rangeSt.RangeAdd(0, 1, 10))
+	require.Equal(t, st.PreOrder()[0], rangeSt.PreOrder()[0])
+
+	require.NoError(t, st.Add(2, 2))
+	require.NoError(t, rangeSt.RangeAdd(2, 3, 2))
+	for _, tree := range []*SegTree[int]{st, rangeSt} {
+		res, err := tree.Query(0, 3)
+		require.NoError(t, err)
+		require.Equal(t, 20, res)
+		res, err = tree.Query(1, 3)
+		require.NoError(t, err)
+		require.Equal(t, 8, res)
+	}
+}
+
+func TestMonoid(t *testing.T) {
+	require.Equal(t, 0, SumMonoid[int]{}.Identity())
+	require.Equal(t, math.MinInt, MaxMonoid[int]{Lowest: math.MinInt}.Identity())
+	require.Equal(t, math.MaxInt, MinMonoid[int]{Highest: math.MaxInt}.Identity())
+	require.Equal(t, "ab", SumMonoid[string]{}.Combine("a", "b"))
+
+	// 元素类型的边界
+	require.Equal(t, math.MinInt, lowest[int]())
+	require.Equal(t, math.MaxInt, highest[int]())
+	require.Equal(t, int8(math.MinInt8), lowest[int8]())
+	require.Equal(t, int8(math.MaxInt8), highest[int8]())
+	require.Equal(t, uint16(0), lowest[uint16]())
+	require.Equal(t, uint16(math.MaxUint16), highest[uint16]())
+	require.Equal(t, uint64(math.MaxUint64), highest[uint64]())
+	require.Equal(t, math.Inf(-1), lowest[float64]())
+	require.Equal(t, float32(math.Inf(1)), highest[float32]())
+
+	// 单位元不会影响全为负数或者全为最大值的序列
+	maxTree := NewMaxSegTree([]int8{-3, -1, -2})
+	res8, err := maxTree.Query(0, 3)
+	require.NoError(t, err)
+	require.Equal(t, int8(-1), res8)
+	minTree := NewMinSegTree([]uint8{255, 255})
+	resU8, err := minTree.Query(0, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint8(255), resU8)
+	sumTree := NewSumSegTree([]float64{0.5, 1.5})
+	require.NoError(t, sumTree.Add(0, 1))
+	resF, err := sumTree.Query(0, 2)
+	require.NoError(t, err)
+	require.Equal(t, 3.0, resF)
+
+	st, err := NewIterSegTree([]int{5, 6, 7}, NewMinSegTree([]int{}).m)
+	require.NoError(t, err)
+	res, err := st.Query(0, 3)
+	require.NoError(t, err)
+	require.Equal(t, 5, res)
+
+	// AggMonoid 没有可靠的单位元，不能用于 IterSegTree
+	_, err = NewIterSegTree([]int{1}, AggMonoid[int](Min))
+	require.ErrorIs(t, err, ErrNoIdentity)
+	_, err = NewIterSegTree([]int{1}, AggMonoid(func(seg []int) int { return len(seg) }))
+	require.ErrorIs(t, err, ErrNoIdentity)
+
+	testcnt := 110
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	fArr := []AggFunc[int]{Sum[int], Max[int], Min[int]}
+	mArr := []Monoid[int]{SumMonoid[int]{}, MaxMonoid[int]{Lowest: math.MinInt}, MinMonoid[int]{Highest: math.MaxInt}}
+	for i := range testcnt {
+		cnt := min((i+1)*10, 1000)
+		nums := GenNumList(cnt, 100000)
+		for j := range nums {
+			nums[j] -= 50000
+		}
+		f, m := fArr[i%3], mArr[i%3]
+		st := NewSegTreeMonoid(nums, m)
+		require.Equal(t, f(nums), st.root.aggVal)
+
+		for range 100 {
+			start := rd.IntN(cnt)
+			end := min(start+rd.IntN(cnt-start)+1, cnt)
+			res, err := st.Query(start, end)
+			require.NoError(t, err)
+			require.Equal(t, f(nums[start:end]), res)
+		}
+	}
+}
+
+func TestQueryNoAlloc(t *testing.T) {
+	st := NewSegTreeMonoid(GenNumList(1000, 100000), SumMonoid[int]{})
+	allocs := testing.AllocsPerRun(100, func() {
+		st.Query(123, 877)
+	})
+	require.Zero(t, allocs)
+
+	for _, newTree := range []func([]int) *SegTree[int]{NewSumSegTree[int], NewMaxSegTree[int], NewMinSegTree[int]} {
+		st = newTree(GenNumList(1000, 100000))
+		allocs = testing.AllocsPerRun(100, func() {
+			st.Query(123, 877)
+			st.Update(500, 1)
+		})
+		require.Zero(t, allocs)
+	}
+
+	st = NewLazySegTreeMonoid(GenNumList(1000, 100000), SumMonoid[int]{}, SumAction[int]{})
+	require.NoError(t, st.RangeAdd(100, 900, 1))
+	require.NoError(t, st.Add(500, 1))
+	allocs = testing.AllocsPerRun(100, func() {
+		st.Query(123, 877)
+	})
+	require.Zero(t, allocs)
+}
+
+func TestAnyType(t *testing.T) {
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	nums := GenNumList(500, 1000)
+
+	t.Run("MinArgMin", func(t *testing.T) {
+		type minArg struct{ val, idx int }
+		m := NewMonoid(minArg{math.MaxInt, -1}, func(a, b minArg) minArg {
+			if b.val < a.val || (b.val == a.val && b.idx < a.idx) {
+				return b
+			}
+			return a
+		})
+		seg := make([]minArg, len(nums))
+		for i, v := range nums {
+			seg[i] = minArg{v, i}
+		}
+		sts := []arrayTree[minArg]{pointerTree[minArg]{NewSegTreeMonoid(seg, m)}, NewArraySegTree(seg, m), mustIterSegTree(t, seg, m)}
+		for range 100 {
+			start := rd.IntN(len(nums))
+			end := min(start+rd.IntN(len(nums)-start)+1, len(nums))
+			want := minArg{nums[start], start}
+			for i := start; i < end; i++ {
+				if nums[i] < want.val {
+					want = minArg{nums[i], i}
+				}
+			}
+			for _, st := range sts {
+				res, err := st.Query(start, end)
+				require.NoError(t, err)
+				require.Equal(t, want, res)
+			}
+		}
+	})
+
+	t.Run("SumCount", func(t *testing.T) {
+		type sumCnt struct{ sum, cnt int }
+		m := NewMonoid(sumCnt{}, func(a, b sumCnt) sumCnt {
+			return sumCnt{a.sum + b.sum, a.cnt + b.cnt}
+		})
+		seg := make([]sumCnt, len(nums))
+		for i, v := range nums {
+			seg[i] = sumCnt{v, 1}
+		}
+		st := NewSegTreeMonoid(seg, m)
+		require.ErrorIs(t, st.Add(0, sumCnt{}), ErrNoAdd)
+		require.ErrorIs(t, NewLazySegTreeMonoid(seg, m, nil).Add(0, sumCnt{}), ErrNoAdd)
+
+		require.NoError(t, st.Update(0, sumCnt{nums[0] + 1, 1}))
+		res, err := st.Query(0, len(nums))
+		require.NoError(t, err)
+		require.Equal(t, sumCnt{Sum(nums) + 1, len(nums)}, res)
+	})
+
+	t.Run("Matrix", func(t *testing.T) {
+		// 2x2 矩阵连乘，不满足交换律
+		type mat [4]int
+		mul := func(a, b mat) mat {
+			return mat{
+				a[0]*b[0] + a[1]*b[2], a[0]*b[1] + a[1]*b[3],
+				a[2]*b[0] + a[3]*b[2], a[2]*b[1] + a[3]*b[3],
+			}
+		}
+		m := NewMonoid(mat{1, 0, 0, 1}, mul)
+		seg := make([]mat, 64)
+		for i := range seg {
+			seg[i] = mat{rd.IntN(3), rd.IntN(3), rd.IntN(3), rd.IntN(3)}
+		}
+		sts := []arrayTree[mat]{pointerTree[mat]{NewSegTreeMonoid(seg, m)}, NewArraySegTree(seg, m), mustIterSegTree(t, seg, m)}
+		for l := 0; l < len(seg); l++ {
+			want := m.Identity()
+			for r := l + 1; r <= len(seg); r++ {
+				want = mul(want, seg[r-1])
+				for _, st := range sts {
+					res, err := st.Query(l, r)
+					require.NoError(t, err)
+					require.Equal(t, want, res)
+				}
+			}
+		}
+	})
+}
+
+// sumCnt 区间和以及元素个数
+type sumCnt struct{ sum, cnt int }
+
+// sumCntAction 区间加法只修改 sum，增量的 cnt 没有意义，合并后为 0
+type sumCntAction struct{}
+
+func (sumCntAction) Add(agg, delta sumCnt, n int) sumCnt {
+	return sumCnt{agg.sum + delta.sum*n, agg.cnt}
+}
+
+func (sumCntAction) Assign(val sumCnt, n int) sumCnt {
+	return sumCnt{val.sum * n, n}
+}
+
+func (sumCntAction) Compose(a, b sumCnt) sumCnt {
+	return sumCnt{a.sum + b.sum, 0}
+}
+
+func TestRangeUpdateAnyType(t *testing.T) {
+	rd := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), uint64(time.Now().UnixNano())))
+	m := NewMonoid(sumCnt{}, func(a, b sumCnt) sumCnt {
+		return sumCnt{a.sum + b.sum, a.cnt + b.cnt}
+	})
+	nums := GenNumList(500, 1000)
+	seg := make([]sumCnt, len(nums))
+	for i, v := range nums {
+		seg[i] = sumCnt{v, 1}
+	}
+	st := NewLazySegTreeMonoid(seg, m, sumCntAction{})
+
+	// 叶子节点以及单点加法需要通过 Add 作用在元素上，元素个数保持不变
+	for range 200 {
+		l := rd.IntN(len(nums))
+		r := min(l+rd.IntN(len(nums)-l)+1, len(nums))
+		v := rd.IntN(2000) - 1000
+		switch rd.IntN(3) {
+		case 0:
+			for j := l; j < r; j++ {
+				nums[j] += v
+			}
+			require.NoError(t, st.RangeAdd(l, r, sumCnt{v, 0}))
+		case 1:
+			for j := l; j < r; j++ {
+				nums[j] = v
+			}
+			require.NoError(t, st.RangeAssign(l, r, sumCnt{v, 1}))
+		case 2:
+			nums[l] += v
+			require.NoError(t, st.Add(l, sumCnt{v, 0}))
+		}
+
+		start := rd.IntN(len(nums))
+		end := min(start+rd.IntN(len(nums)-start)+1, len(nums))
+		res, err := st.Query(start, end)
+		require.NoError(t, err)
+		require.Equal(t, sumCnt{Sum(nums[start:end]), end - start}, res)
+	}
+}
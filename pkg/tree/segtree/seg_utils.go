@@ -12,12 +12,12 @@ import (
 // seg 表示原始序列
 // start, end 表示区间 [start, end)，左闭右开
 // cnt 表示节点的数量
-func buildRecursive[T cmp.Ordered](seg []T, start, end int, f AggFunc[T]) (root *segNode[T], cnt int) {
+func buildRecursive[T any](seg []T, start, end int, m Monoid[T]) (root *segNode[T], cnt int) {
 	if start >= end {
 		return nil, 0
 	}
 
-	root = newSegNode(seg, start, end, f)
+	root = newSegNode(seg, start, end)
 	if end-start == 1 {
 		// 叶子节点
 		return root, 1
@@ -33,31 +33,31 @@ func buildRecursive[T cmp.Ordered](seg []T, start, end int, f AggFunc[T]) (root
 		// 对于长度大于 2 的序列的划分，这里不应该出现划分出来的区间长度为 0 的情况
 		panic("should not happen")
 	}
-	root.left, lcnt = buildRecursive(seg, l, mid, f)
-	root.right, rcnt = buildRecursive(seg, mid, r, f)
+	root.left, lcnt = buildRecursive(seg, l, mid, m)
+	root.right, rcnt = buildRecursive(seg, mid, r, m)
+	root.pushUp(m)
 	return root, lcnt + rcnt + 1
 }
 
 // build 非递归构建线段树
 //
-// 相当于通过层序遍历构建线段树
-func build[T cmp.Ordered](seg []T, f AggFunc[T]) (root *segNode[T], cnt int) {
+// 相当于通过层序遍历构建线段树，最后按照层序遍历的逆序自底向上计算聚合值
+func build[T any](seg []T, m Monoid[T]) (root *segNode[T], cnt int) {
 	segLen := len(seg)
 	if segLen == 0 {
 		return nil, 0
 	}
 
-	root = newSegNode(seg, 0, segLen, f)
+	root = newSegNode(seg, 0, segLen)
 	if segLen == 1 {
 		// 叶子节点
 		return root, 1
 	}
 
+	// queue 中保留所有出队的节点，即层序遍历的结果
 	queue := []*segNode[T]{root}
-	for len(queue) > 0 {
-		top := queue[0]
-		queue = queue[1:]
-		cnt++
+	for ; cnt < len(queue); cnt++ {
+		top := queue[cnt]
 		if (top.end - top.start) <= 1 {
 			// 叶子节点
 			continue
@@ -71,21 +71,28 @@ func build[T cmp.Ordered](seg []T, f AggFunc[T]) (root *segNode[T], cnt int) {
 			// 对于长度大于 2 的序列的划分，这里不应该出现划分出来的区间长度为 0 的情况
 			panic("should not happen")
 		}
-		top.left = newSegNode(top.seg, l, mid, f)
-		top.right = newSegNode(top.seg, mid, r, f)
+		top.left = newSegNode(top.seg, l, mid)
+		top.right = newSegNode(top.seg, mid, r)
 		queue = append(queue, top.left, top.right)
 	}
+
+	// 孩子总是排在父节点之后，逆序遍历保证计算父节点时孩子已经计算完成
+	for i := len(queue) - 1; i >= 0; i-- {
+		if !queue[i].isLeaf() {
+			queue[i].pushUp(m)
+		}
+	}
 	return root, cnt
 }
 
 // buildBottomUp 自底向上构建线段树
 //
 // cnt 表示节点的数量
-func buildBottomUp[T cmp.Ordered](seg []T, f AggFunc[T]) (root *segNode[T], cnt int) {
+func buildBottomUp[T any](seg []T, m Monoid[T]) (root *segNode[T], cnt int) {
 	queue, next := list.New(), list.New()
 	// 初始化叶子节点
 	for i := range seg {
-		queue.PushBack(newSegNode(seg, i, i+1, f))
+		queue.PushBack(newSegNode(seg, i, i+1))
 		cnt++
 	}
 
@@ -101,9 +108,10 @@ func buildBottomUp[T cmp.Ordered](seg []T, f AggFunc[T]) (root *segNode[T], cnt
 		queue.Remove(l)
 		queue.Remove(r)
 		start, end := min(lVal.start, rVal.start), max(lVal.end, rVal.end)
-		peek := newSegNode(seg, start, end, f)
+		peek := newSegNode(seg, start, end)
 		peek.left = l.Value.(*segNode[T])
 		peek.right = r.Value.(*segNode[T])
+		peek.pushUp(m)
 		// 合并后存储到下一层
 		next.PushBack(peek)
 		cnt++
@@ -123,11 +131,23 @@ func buildBottomUp[T cmp.Ordered](seg []T, f AggFunc[T]) (root *segNode[T], cnt
 // ErrNotInRang 表示查询区间不在当前序列的范围内
 var ErrNotInRang = errors.New("not in range")
 
+// errNoIntersect 表示两个区间没有交集
+var errNoIntersect = errors.New("no intersect")
+
+// ErrNoAdd 表示线段树的元素类型没有加法，不支持 Add
+var ErrNoAdd = errors.New("no addition for element type")
+
+// ErrNoAction 表示线段树没有设置区间修改的 Action，不支持区间修改
+var ErrNoAction = errors.New("no action for range update")
+
+// ErrNoIdentity 表示 Monoid 没有可靠的单位元，不能用于需要合并单位元的线段树
+var ErrNoIdentity = errors.New("no identity for monoid")
+
 // query 线段树的区间查询（递归）
 //
 // [l, r) 表示查询区间，左闭右开
 // 返回区间 [l, r) 的和、最大值、最小值
-func query[T cmp.Ordered](root *segNode[T], l, r int, f AggFunc[T]) (T, error) {
+func query[T any](root *segNode[T], l, r int, m Monoid[T], act Action[T]) (T, error) {
 	if root == nil {
 		return *new(T), errors.New("invaliTTegNode")
 	}
@@ -146,18 +166,19 @@ func query[T cmp.Ordered](root *segNode[T], l, r int, f AggFunc[T]) (T, error) {
 		lVal, rVal T
 		lErr, rErr error
 	)
-	// 情况 3：处理左右孩子有交集的情况
+	// 情况 3：处理左右孩子有交集的情况，先下推懒标记
+	pushDown(root, act)
 	if root.left != nil {
 		var start, end int
 		if start, end, lErr = getIntersect(root.left, l, r); lErr == nil {
-			lVal, lErr = query(root.left, start, end, f)
+			lVal, lErr = query(root.left, start, end, m, act)
 		}
 	}
 
 	if root.right != nil {
 		var start, end int
 		if start, end, rErr = getIntersect(root.right, l, r); rErr == nil {
-			rVal, rErr = query(root.right, start, end, f)
+			rVal, rErr = query(root.right, start, end, m, act)
 		}
 	}
 
@@ -166,14 +187,116 @@ func query[T cmp.Ordered](root *segNode[T], l, r int, f AggFunc[T]) (T, error) {
 	} else if rErr != nil {
 		return lVal, lErr
 	} else {
-		return f([]T{lVal, rVal}), nil
+		return m.Combine(lVal, rVal), nil
+	}
+}
+
+// update 单点修改（递归）
+//
+// 找到下标 i 对应的叶子节点，将其修改为 fn(原值)，回溯时重新计算路径上的聚合值
+func update[T any](root *segNode[T], i int, fn func(old T) T, m Monoid[T], act Action[T]) {
+	if root.isLeaf() {
+		root.seg[i] = fn(root.seg[i])
+		root.aggVal = root.seg[i]
+		return
+	}
+
+	// 先下推懒标记，保证叶子节点的值是最新的
+	pushDown(root, act)
+	if i < root.left.end {
+		update(root.left, i, fn, m, act)
+	} else {
+		update(root.right, i, fn, m, act)
+	}
+	root.pushUp(m)
+}
+
+// rangeUpdate 区间修改（递归）
+//
+// [l, r) 表示修改区间，左闭右开；完全覆盖的节点只记录懒标记，不再向下递归
+func rangeUpdate[T any](root *segNode[T], l, r int, tag lazyTag[T], m Monoid[T], act Action[T]) {
+	// 情况 1：无交集
+	if root.start >= r || root.end <= l {
+		return
 	}
+
+	// 情况 2：[start, end) 完全包含于 [l, r)
+	if root.start >= l && root.end <= r {
+		applyTag(root, tag, act)
+		return
+	}
+
+	// 情况 3：部分相交，先下推之前的标记，再分别修改左右孩子
+	pushDown(root, act)
+	rangeUpdate(root.left, l, r, tag, m, act)
+	rangeUpdate(root.right, l, r, tag, m, act)
+	root.pushUp(m)
+}
+
+// applyTag 将修改作用在整个节点上
+//
+// 叶子节点直接修改原始序列，非叶子节点更新聚合值并与已有的懒标记合并
+func applyTag[T any](root *segNode[T], tag lazyTag[T], act Action[T]) {
+	if root.isLeaf() {
+		switch tag.kind {
+		case tagAdd:
+			root.seg[root.start] = act.Add(root.seg[root.start], tag.val, 1)
+		case tagAssign:
+			root.seg[root.start] = act.Assign(tag.val, 1)
+		}
+		root.aggVal = root.seg[root.start]
+		return
+	}
+
+	n := root.end - root.start
+	switch tag.kind {
+	case tagAdd:
+		root.aggVal = act.Add(root.aggVal, tag.val, n)
+		switch root.tag.kind {
+		case tagNone:
+			root.tag = tag
+		case tagAdd:
+			// 两次加法合并为一次
+			root.tag.val = act.Compose(root.tag.val, tag.val)
+		case tagAssign:
+			// 赋值后再加上 delta，等价于赋值为 val + delta
+			root.tag.val = act.Add(root.tag.val, tag.val, 1)
+		}
+	case tagAssign:
+		root.aggVal = act.Assign(tag.val, n)
+		root.tag = tag
+	}
+}
+
+// pushDown 将懒标记下推给左右孩子
+func pushDown[T any](root *segNode[T], act Action[T]) {
+	if root.tag.kind == tagNone || root.isLeaf() {
+		return
+	}
+	applyTag(root.left, root.tag, act)
+	applyTag(root.right, root.tag, act)
+	root.tag = lazyTag[T]{}
+}
+
+// pushAll 将所有懒标记下推到叶子节点，使原始序列与聚合值保持一致
+func pushAll[T any](root *segNode[T], act Action[T]) {
+	if root == nil || root.isLeaf() {
+		return
+	}
+	pushDown(root, act)
+	pushAll(root.left, act)
+	pushAll(root.right, act)
+}
+
+// plus 有序类型的加法
+func plus[T cmp.Ordered](a, b T) T {
+	return a + b
 }
 
 // 获取交集区间
-func getIntersect[T cmp.Ordered](root *segNode[T], l, r int) (int, int, error) {
+func getIntersect[T any](root *segNode[T], l, r int) (int, int, error) {
 	if root.end > l || root.start < r {
 		return max(root.start, l), min(root.end, r), nil
 	}
-	return -1, -1, errors.New("no intersect")
+	return -1, -1, errNoIntersect
 }
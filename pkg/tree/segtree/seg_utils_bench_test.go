@@ -10,7 +10,7 @@ func BenchmarkBuild(b *testing.B) {
 		cnt := min((i+1)*100, 100000)
 		nums := GenNumList(cnt, 100000)
 		b.StartTimer()
-		build(nums, Sum)
+		build(nums, SumMonoid[int]{})
 	}
 }
 
@@ -21,7 +21,7 @@ func BenchmarkBuildRecursive(b *testing.B) {
 		cnt := min((i+1)*100, 100000)
 		nums := GenNumList(cnt, 100000)
 		b.StartTimer()
-		buildRecursive(nums, 0, len(nums), Sum)
+		buildRecursive(nums, 0, len(nums), SumMonoid[int]{})
 	}
 }
 
@@ -33,6 +33,6 @@ func BenchmarkBuildBottomUp(b *testing.B) {
 		cnt := min((i+1)*100, 100000)
 		nums := GenNumList(cnt, 100000)
 		b.StartTimer()
-		buildBottomUp(nums, Sum)
+		buildBottomUp(nums, SumMonoid[int]{})
 	}
 }
@@ -39,9 +39,11 @@ func TestBuild(t *testing.T) {
 	testcnt := 110
 	for i := range testcnt {
 		nums := GenNumList(1000, 10000)
-		seg, cnt := build(nums, Min)
-		segRe, cntRe := buildRecursive(nums, 0, len(nums), Min)
+		seg, cnt := build(nums, AggMonoid[int](Min))
+		segRe, cntRe := buildRecursive(nums, 0, len(nums), AggMonoid[int](Min))
 		require.Equal(t, cnt, cntRe)
+		require.Equal(t, Min(nums), seg.aggVal)
+		require.Equal(t, Min(nums), segRe.aggVal)
 
 		st, stRe := &SegTree[int]{
 			root: seg,
@@ -74,22 +76,22 @@ func TestBuild(t *testing.T) {
 }
 
 func TestBuild_Special(t *testing.T) {
-	seg, cnt := build([]int{}, Sum)
+	seg, cnt := build([]int{}, AggMonoid[int](Sum))
 	require.Nil(t, seg)
 	require.Equal(t, 0, cnt)
 
-	seg, cnt = build([]int{1}, Sum)
+	seg, cnt = build([]int{1}, AggMonoid[int](Sum))
 	require.NotNil(t, seg)
 	require.Equal(t, 1, cnt)
 	require.Equal(t, 1, len(seg.seg))
 }
 
 func TestBuildRecursive_Special(t *testing.T) {
-	seg, cnt := buildRecursive([]int{}, 0, 0, Sum)
+	seg, cnt := buildRecursive([]int{}, 0, 0, AggMonoid[int](Sum))
 	require.Nil(t, seg)
 	require.Equal(t, 0, cnt)
 
-	seg, cnt = buildRecursive([]int{1}, 0, 1, Sum)
+	seg, cnt = buildRecursive([]int{1}, 0, 1, AggMonoid[int](Sum))
 	require.NotNil(t, seg)
 	require.Equal(t, 1, cnt)
 	require.Equal(t, 1, len(seg.seg))
@@ -99,10 +101,11 @@ func TestBuildBottomUp(t *testing.T) {
 	testcnt := 110
 	for i := range testcnt {
 		nums := GenNumList(1000, 10000)
-		segBtUp, cntBtUp := buildBottomUp(nums, Max)
-		_, cntRe := buildRecursive(nums, 0, len(nums), Max)
+		segBtUp, cntBtUp := buildBottomUp(nums, AggMonoid[int](Max))
+		_, cntRe := buildRecursive(nums, 0, len(nums), AggMonoid[int](Max))
 		require.NotNil(t, segBtUp)
 		require.Equal(t, cntBtUp, cntRe)
+		require.Equal(t, Max(nums), segBtUp.aggVal)
 
 		st := &SegTree[int]{
 			root: segBtUp,